@@ -0,0 +1,468 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/types"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/silvacheung/dix"
+)
+
+const dixPath = "github.com/silvacheung/dix"
+
+// generator is writing the injectors of the package
+type generator struct {
+	pkg     *types.Package
+	imports map[string]string // imports is the import name of path
+	used    map[string]string // used is the import path of name
+	tags    []string          // tags is the dix tag values of the tag variables
+	tagVars map[string]string // tagVars is the tag variable of dix tag value
+	funcs   map[*types.TypeName]string
+	queue   []*types.Named // queue is the local struct types need injector
+	body    bytes.Buffer
+}
+
+func newGenerator(pkg *types.Package) *generator {
+	g := &generator{
+		pkg:     pkg,
+		imports: make(map[string]string),
+		used:    make(map[string]string),
+		tagVars: make(map[string]string),
+		funcs:   make(map[*types.TypeName]string),
+	}
+	for _, path := range []string{"context", "errors", dixPath} {
+		g.importName(path, path[strings.LastIndex(path, "/")+1:])
+	}
+	return g
+}
+
+// generate return the formatted source of the injectors of the type names, all structs with dix tags if empty
+func (g *generator) generate(names []string) ([]byte, error) {
+	targets, err := g.targets(names)
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("package `%s` has no struct with %s tags", g.pkg.Name(), dix.TagDix)
+	}
+
+	for _, t := range targets {
+		g.funcs[t.Obj()] = "Inject" + t.Obj().Name()
+	}
+	for _, t := range targets {
+		if err = g.cycled(t, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	invoke := g.tagVar(dix.TagSymbol + ":" + dix.TagInvoke)
+	for _, t := range targets {
+		g.newFunc(t, invoke)
+	}
+	g.queue = append(g.queue, targets...)
+	for done := make(map[*types.Named]bool); len(g.queue) > 0; {
+		t := g.queue[0]
+		g.queue = g.queue[1:]
+		if !done[t] {
+			done[t] = true
+			if err = g.injectFunc(t); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by dixgen. DO NOT EDIT.\n\npackage %s\n\nimport (\n", g.pkg.Name())
+	paths := make([]string, 0, len(g.imports))
+	for path := range g.imports {
+		paths = append(paths, path)
+	}
+	// the standard packages first, then the others
+	sort.Slice(paths, func(i, j int) bool {
+		if std(paths[i]) != std(paths[j]) {
+			return std(paths[i])
+		}
+		return paths[i] < paths[j]
+	})
+	for i, path := range paths {
+		if i > 0 && std(path) != std(paths[i-1]) {
+			src.WriteString("\n")
+		}
+		if name := g.imports[path]; name != path[strings.LastIndex(path, "/")+1:] {
+			fmt.Fprintf(&src, "\t%s %s\n", name, strconv.Quote(path))
+		} else {
+			fmt.Fprintf(&src, "\t%s\n", strconv.Quote(path))
+		}
+	}
+	src.WriteString(")\n\n// the dix tags of the fields, shared and read-only\nvar (\n")
+	for i, tag := range g.tags {
+		fmt.Fprintf(&src, "\tdixTag%d = dix.NewTag(%s)\n", i, strconv.Quote(tag))
+	}
+	src.WriteString(")\n")
+	src.Write(g.body.Bytes())
+
+	out, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated source: %w\n%s", err, src.Bytes())
+	}
+	return out, nil
+}
+
+// std is checked the import path is a standard package
+func std(path string) bool {
+	return !strings.Contains(strings.Split(path, "/")[0], ".")
+}
+
+// targets return the named struct types of the names, all structs with dix tags if empty
+func (g *generator) targets(names []string) ([]*types.Named, error) {
+	scope := g.pkg.Scope()
+	if len(names) == 0 {
+		names = scope.Names()
+		var targets []*types.Named
+		for _, name := range names {
+			if t, ok := local(scope.Lookup(name)); ok && tagged(t.Underlying().(*types.Struct)) {
+				targets = append(targets, t)
+			}
+		}
+		return targets, nil
+	}
+
+	var targets []*types.Named
+	for _, name := range names {
+		t, ok := local(scope.Lookup(strings.TrimSpace(name)))
+		if !ok {
+			return nil, fmt.Errorf("type `%s` is not a struct type of package `%s`", name, g.pkg.Name())
+		}
+		targets = append(targets, t)
+	}
+	return targets, nil
+}
+
+// local return the named struct type of the object, the generic types are not supported
+func local(obj types.Object) (*types.Named, bool) {
+	tn, ok := obj.(*types.TypeName)
+	if !ok || tn.IsAlias() {
+		return nil, false
+	}
+	t, ok := tn.Type().(*types.Named)
+	if !ok || t.TypeParams().Len() > 0 {
+		return nil, false
+	}
+	_, ok = t.Underlying().(*types.Struct)
+	return t, ok
+}
+
+// tagged is checked the struct has dix tag fields
+func tagged(s *types.Struct) bool {
+	for i := 0; i < s.NumFields(); i++ {
+		if _, ok := reflect.StructTag(s.Tag(i)).Lookup(dix.TagDix); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// fields is calling fn with the exported dix tag fields of struct and the parsed tags
+func fields(s *types.Struct, fn func(f *types.Var, val string, tag *dix.Tag) error) error {
+	for i := 0; i < s.NumFields(); i++ {
+		f := s.Field(i)
+		val, ok := reflect.StructTag(s.Tag(i)).Lookup(dix.TagDix)
+		if !ok || !f.Exported() {
+			continue
+		}
+
+		tag, err := dix.ParseTag(val)
+		if err != nil {
+			return fmt.Errorf("field `%s`: %w", f.Name(), err)
+		}
+		if err = fn(f, val, tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// injector return the local named struct type wired by the generated injector, the `from:?` field of type t create it
+func (g *generator) injector(t types.Type) (*types.Named, bool) {
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	n, ok := t.(*types.Named)
+	if !ok || n.Obj().Pkg() != g.pkg {
+		return nil, false
+	}
+	return local(n.Obj())
+}
+
+// cycled is checked the struct recurrence of the generated injectors, the path is the fields from the root
+func (g *generator) cycled(t *types.Named, path []string) error {
+	name := g.pkg.Name() + "." + t.Obj().Name()
+	for i, step := range path {
+		if strings.HasPrefix(step, name+".") {
+			return fmt.Errorf("cycled dependency %s -> %s", strings.Join(path[i:], " -> "), name)
+		}
+	}
+
+	return fields(t.Underlying().(*types.Struct), func(f *types.Var, val string, tag *dix.Tag) error {
+		if tag.GetSymbol() != dix.TagInvoke {
+			return nil
+		}
+		if n, ok := g.injector(f.Type()); ok {
+			return g.cycled(n, append(path, name+"."+f.Name()))
+		}
+		return nil
+	})
+}
+
+// newFunc is writing NewT resolving the type like dix.Resolve
+func (g *generator) newFunc(t *types.Named, invoke string) {
+	name := g.typeString(t)
+	fmt.Fprintf(&g.body, "\n// New%s is resolving %s with the Container like dix.Resolve, the unbound %s is created without reflection\n", t.Obj().Name(), name, name)
+	fmt.Fprintf(&g.body, "func New%s(ctx context.Context, c *dix.Container) (x %s, err error) {\n", t.Obj().Name(), name)
+	fmt.Fprintf(&g.body, "if dix.Bound[%s](c, %s) {\nreturn dix.ResolveTag[%s](ctx, c, %s)\n}\n", name, invoke, name, invoke)
+	fmt.Fprintf(&g.body, "var v %s\nif err = %s(ctx, c, &v); err != nil {\nreturn x, err\n}\nreturn v, nil\n}\n", name, g.funcName(t))
+}
+
+// injectFunc is writing the injector of the struct type like Container.Inject
+func (g *generator) injectFunc(t *types.Named) error {
+	name := g.typeString(t)
+	fn := g.funcName(t)
+	fmt.Fprintf(&g.body, "\n// %s is injecting the zero value dix tag fields of x like Container.Inject without reflection\n", fn)
+	fmt.Fprintf(&g.body, "func %s(ctx context.Context, c *dix.Container, x *%s) error {\nvar errs []error\n", fn, name)
+
+	err := fields(t.Underlying().(*types.Struct), func(f *types.Var, val string, tag *dix.Tag) error {
+		if strings.Contains(f.Type().String(), "invalid type") {
+			return fmt.Errorf("`%s` field `%s` type is invalid, the package does not type check", t, f.Name())
+		}
+		if tag.GetSymbol() != "" {
+			g.field(t, f, val, tag)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	g.body.WriteString("if len(errs) > 0 {\nreturn errors.Join(errs...)\n}\n")
+	if g.initialized(types.NewPointer(t)) {
+		g.importName("fmt", "fmt")
+		fmt.Fprintf(&g.body, "if err := x.Init(ctx); err != nil {\nreturn fmt.Errorf(%s, err)\n}\n", strconv.Quote("`*"+g.pkg.Name()+"."+t.Obj().Name()+"` init error: %w"))
+	}
+	g.body.WriteString("return nil\n}\n")
+	return nil
+}
+
+// field is writing the resolution of the struct field
+func (g *generator) field(owner *types.Named, f *types.Var, val string, tag *dix.Tag) {
+	ft := f.Type()
+	typ := g.typeString(ft)
+	dst := "x." + f.Name()
+	tv := g.tagVar(val)
+	fail := func(err string) string {
+		return fmt.Sprintf("errs = dix.AppendFieldError[%s](errs, %s, %s, %s)\n", g.typeString(owner), strconv.Quote(f.Name()), strconv.Quote(val), err)
+	}
+	resolve := func(call string) string {
+		return fmt.Sprintf("if v, err := %s[%s](ctx, c, %s); err != nil {\n%s} else {\n%s = v\n}\n", call, typ, tv, fail("err"), dst)
+	}
+
+	fmt.Fprintf(&g.body, "if %s {\n", g.zero(dst, ft))
+	defer g.body.WriteString("}\n")
+
+	// the Provider is called directly
+	if tag.GetSymbol() != dix.TagInvoke {
+		g.body.WriteString(resolve("dix.Provide"))
+		return
+	}
+
+	// the bound type is resolved with the Container, otherwise created like invoke
+	create, ok := g.create(ft, dst, tag, fail)
+	if !ok {
+		g.body.WriteString(resolve("dix.ResolveTag"))
+		return
+	}
+	fmt.Fprintf(&g.body, "if dix.Bound[%s](c, %s) {\n%s}", typ, tv, resolve("dix.ResolveTag"))
+	if create != "" {
+		fmt.Fprintf(&g.body, " else {\n%s}", create)
+	}
+	g.body.WriteString("\n")
+}
+
+// create return the code creating the unbound type like invoke, false if the type need reflection
+func (g *generator) create(t types.Type, dst string, tag *dix.Tag, fail func(string) string) (string, bool) {
+	typ := g.typeString(t)
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		return "", u.Kind() != types.Invalid
+	case *types.Interface, *types.Signature, *types.Array:
+		return "", true
+	case *types.Chan:
+		return fmt.Sprintf("%s = make(%s, %d)\n", dst, typ, tag.GetChanBuf()), true
+	case *types.Map:
+		return fmt.Sprintf("%s = make(%s, %d)\n", dst, typ, tag.GetMapSize()), true
+	case *types.Slice:
+		return fmt.Sprintf("%s = make(%s, %d, %d)\n", dst, typ, tag.GetSliceLen(), tag.GetSliceCap()), true
+	case *types.Pointer:
+		if _, ok := u.Elem().Underlying().(*types.Struct); !ok {
+			return g.finish(fmt.Sprintf("v := new(%s)\n", g.typeString(u.Elem())), "", dst, fail), true
+		}
+		if n, ok := g.injector(t); ok {
+			g.queue = append(g.queue, n)
+			return g.finish(fmt.Sprintf("v := new(%s)\n", g.typeString(n)), g.funcName(n)+"(ctx, c, v)", dst, fail), true
+		}
+		if g.plain(u.Elem()) {
+			return g.finish(fmt.Sprintf("v := new(%s)\n", g.typeString(u.Elem())), g.initCall(t, "v"), dst, fail), true
+		}
+	case *types.Struct:
+		if n, ok := g.injector(t); ok {
+			g.queue = append(g.queue, n)
+			return g.finish(fmt.Sprintf("var v %s\n", typ), g.funcName(n)+"(ctx, c, &v)", dst, fail), true
+		}
+		if g.plain(t) {
+			return g.finish(fmt.Sprintf("var v %s\n", typ), g.initCall(types.NewPointer(t), "(&v)"), dst, fail), true
+		}
+	}
+	return "", false
+}
+
+// finish return the code declaring v, calling the injector (or Init) and setting the field;
+// The created value is owned by the caller like the invoked instances of DI
+func (g *generator) finish(decl, call, dst string, fail func(string) string) string {
+	if call == "" {
+		return decl + dst + " = v\n"
+	}
+	return fmt.Sprintf("%sif err := %s; err != nil {\n%s} else {\n%s = v\n}\n", decl, call, fail("err"), dst)
+}
+
+// plain is checked the non-local named struct type without dix tags, it is created without the Container
+func (g *generator) plain(t types.Type) bool {
+	n, ok := t.(*types.Named)
+	if !ok || n.TypeParams().Len() > 0 {
+		return false
+	}
+	s, ok := n.Underlying().(*types.Struct)
+	return ok && !tagged(s) && (n.Obj().Pkg() == g.pkg || n.Obj().Exported())
+}
+
+// initCall return the Init call of x if the pointer type implements dix.Initializer
+func (g *generator) initCall(p types.Type, x string) string {
+	if !g.initialized(p) {
+		return ""
+	}
+	g.importName("fmt", "fmt")
+	return fmt.Sprintf("func() error {\nif err := %s.Init(ctx); err != nil {\nreturn fmt.Errorf(%s, err)\n}\nreturn nil\n}()",
+		x, strconv.Quote("`"+types.TypeString(p, func(p *types.Package) string { return p.Name() })+"` init error: %w"))
+}
+
+// zero return the expression checked the value is zero
+func (g *generator) zero(x string, t types.Type) string {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsBoolean != 0:
+			return "!" + x
+		case u.Info()&types.IsString != 0:
+			return x + ` == ""`
+		case u.Info()&types.IsNumeric != 0:
+			return x + " == 0"
+		case u.Kind() == types.UnsafePointer:
+			return x + " == nil"
+		}
+	case *types.Pointer, *types.Slice, *types.Map, *types.Chan, *types.Signature, *types.Interface:
+		return x + " == nil"
+	case *types.Struct, *types.Array:
+		if comparable(t) {
+			return x + " == (" + g.typeString(t) + "{})"
+		}
+	}
+	return "dix.IsZero(" + x + ")"
+}
+
+// comparable is checked `==` never panic, the interfaces may hold the values not comparable
+func comparable(t types.Type) bool {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		return u.Kind() != types.Invalid && u.Kind() != types.UntypedNil
+	case *types.Pointer, *types.Chan:
+		return true
+	case *types.Array:
+		return comparable(u.Elem())
+	case *types.Struct:
+		for i := 0; i < u.NumFields(); i++ {
+			if !comparable(u.Field(i).Type()) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// initialized is checked the type implements dix.Initializer
+func (g *generator) initialized(t types.Type) bool {
+	return method(t, "Init")
+}
+
+// method is checked the method set of type has the method `name(context.Context) error`
+func method(t types.Type, name string) bool {
+	obj, _, _ := types.LookupFieldOrMethod(t, false, nil, name)
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+	sig := fn.Type().(*types.Signature)
+	return sig.Params().Len() == 1 && sig.Params().At(0).Type().String() == "context.Context" &&
+		sig.Results().Len() == 1 && sig.Results().At(0).Type().String() == "error"
+}
+
+// funcName return the injector name of the local struct type, the targets are exported
+func (g *generator) funcName(t *types.Named) string {
+	if name, ok := g.funcs[t.Obj()]; ok {
+		return name
+	}
+	name := "dixInject" + t.Obj().Name()
+	g.funcs[t.Obj()] = name
+	return name
+}
+
+// tagVar return the variable of the dix tag value
+func (g *generator) tagVar(val string) string {
+	if v, ok := g.tagVars[val]; ok {
+		return v
+	}
+	v := "dixTag" + strconv.Itoa(len(g.tags))
+	g.tags = append(g.tags, val)
+	g.tagVars[val] = v
+	return v
+}
+
+// typeString return the type expression in the generated package, the packages are imported
+func (g *generator) typeString(t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string {
+		if p == g.pkg {
+			return ""
+		}
+		return g.importName(p.Path(), p.Name())
+	})
+}
+
+// importName return the name of the imported path, the conflicting names are numbered
+func (g *generator) importName(path, name string) string {
+	if x, ok := g.imports[path]; ok {
+		return x
+	}
+	x := name
+	for i := 2; ; i++ {
+		if _, ok := g.used[x]; !ok {
+			break
+		}
+		x = name + strconv.Itoa(i)
+	}
+	g.imports[path], g.used[x] = x, path
+	return x
+}
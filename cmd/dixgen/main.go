@@ -0,0 +1,94 @@
+// Command dixgen generates the reflection-free injectors of the structs with dix tags.
+//
+// It is driven by go generate in the package of the structs:
+//
+//	//go:generate go run github.com/silvacheung/dix/cmd/dixgen -type App,Server
+//
+// For each type T it writes NewT and InjectT, they resolve the dix tag fields like dix.Resolve and Container.Inject,
+// the same namespace and `from:` semantics, but the unbound types are constructed and wired by static code and the
+// Provider is called directly; the bound types still resolve with the Container.
+// The cycles and the invalid tags are reported at generate time, the Provider value type is checked when compiled.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "the package directory of the structs")
+	names := flag.String("type", "", "the comma separated struct type names, default is all structs with dix tags")
+	out := flag.String("o", "dix_gen.go", "the output file, relative to the package directory")
+	flag.Parse()
+
+	if err := generate(*dir, *names, *out); err != nil {
+		fmt.Fprintln(os.Stderr, "dixgen:", err)
+		os.Exit(1)
+	}
+}
+
+// generate is loading the package of dir and writing the injectors of the types to the output
+func generate(dir, names, out string) error {
+	if !filepath.IsAbs(out) {
+		out = filepath.Join(dir, out)
+	}
+
+	pkg, err := load(dir, out)
+	if err != nil {
+		return err
+	}
+
+	var list []string
+	if names != "" {
+		list = strings.Split(names, ",")
+	}
+
+	src, err := newGenerator(pkg).generate(list)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(out, src, 0o644)
+}
+
+// load is parsing and type checking the package of dir, the output file is excluded;
+// The type errors are ignored, the fields of invalid types are resolved with the Container
+func load(dir, out string) (*types.Package, error) {
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range bp.GoFiles {
+		path := filepath.Join(dir, name)
+		if same(path, out) {
+			continue
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil), Error: func(error) {}}
+	pkg, _ := conf.Check(bp.Name, fset, files, nil)
+	return pkg, nil
+}
+
+// same is checked the paths are the same file
+func same(a, b string) bool {
+	x, e1 := filepath.Abs(a)
+	y, e2 := filepath.Abs(b)
+	return e1 == nil && e2 == nil && x == y
+}
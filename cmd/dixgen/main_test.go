@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	pkg, err := load("testdata/app", "testdata/app/dix_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := newGenerator(pkg).generate([]string{"App"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the generated file is up to date
+	golden, err := os.ReadFile("testdata/app/dix_gen.go")
+	if err != nil || !bytes.Equal(src, golden) {
+		t.Fatalf("testdata/app/dix_gen.go is stale, run go generate ./cmd/dixgen/testdata/app: %v", err)
+	}
+
+	// the cycled dependency is reported at generate time
+	out := filepath.Join(t.TempDir(), "dix_gen.go")
+	if err = generate("testdata/cycle", "", out); err == nil || !strings.Contains(err.Error(), "cycled dependency cycle.A.B -> cycle.B.A -> cycle.A") {
+		t.Fatalf("cycle error unexpected: %v", err)
+	}
+
+	// the invalid dix tag is reported at generate time
+	if err = generate("testdata/badtag", "", out); err == nil || !strings.Contains(err.Error(), "field `Name`: dix: tag `from:'name` at offset 5: unterminated quoted value") {
+		t.Fatalf("tag error unexpected: %v", err)
+	}
+}
+
+func TestGenerated(t *testing.T) {
+	if testing.Short() {
+		t.Skip("go test of the generated package")
+	}
+
+	// the generated injectors wire the same as the reflection
+	cmd := exec.Command("go", "test", "-count=1", "./testdata/app")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("%v\n%s", err, out)
+	}
+}
@@ -0,0 +1,53 @@
+// Package app is the testing package of dixgen
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+//go:generate go run github.com/silvacheung/dix/cmd/dixgen -type App
+
+// Config is provided by the Provider `config`
+type Config struct {
+	DSN string
+}
+
+// DB is created and initialized, the invoked DB is closed by the caller
+type DB struct {
+	Config Config `dix:"from:config"`
+	Ready  bool
+	closed bool
+}
+
+func (db *DB) Init(ctx context.Context) error {
+	db.Ready = db.Config.DSN != ""
+	return nil
+}
+
+func (db *DB) Close(ctx context.Context) error {
+	db.closed = true
+	return nil
+}
+
+// Repo is the value struct
+type Repo struct {
+	DB      *DB            `dix:"from:?"`
+	Timeout time.Duration  `dix:"from:?;namespace:timeout"`
+	Cache   map[string]int `dix:"from:?;map_size:8"`
+}
+
+// App is the root struct
+type App struct {
+	Repo     Repo         `dix:"from:?"`
+	Name     string       `dix:"from:?;namespace:name"`
+	Events   chan string  `dix:"from:?;chan_buf:4"`
+	Tags     []string     `dix:"from:?;slice_len:1;slice_cap:3"`
+	Stringer fmt.Stringer `dix:"from:?"`
+	Clock    *time.Time   `dix:"from:?"`
+	Preset   int          `dix:"from:?"`
+	NoFrom   int          `dix:"namespace:name"`
+	Funcs    []func()     `dix:"from:?"`
+	private  int          `dix:"from:?"`
+}
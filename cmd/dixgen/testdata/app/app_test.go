@@ -0,0 +1,64 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/silvacheung/dix"
+)
+
+type configProvider struct{}
+
+func (configProvider) Symbol() string { return "config" }
+
+func (configProvider) Provide(ctx context.Context, tag *dix.Tag) (any, error) {
+	return Config{DSN: "dsn"}, nil
+}
+
+type name string
+
+func (n name) String() string { return string(n) }
+
+func TestGenerated(t *testing.T) {
+	ctx := context.Background()
+	c := dix.NewContainer()
+	dix.BindIn[dix.Provider](c, configProvider{})
+	dix.BindIn[string](c, "app", "name")
+	dix.BindIn[fmt.Stringer](c, name("stringer"))
+
+	x, err := NewApp(ctx, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	y, err := dix.Resolve[App](ctx, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the generated injector wire the same as the reflection
+	if x.Name != y.Name || x.Stringer != y.Stringer || cap(x.Events) != cap(y.Events) || len(x.Tags) != len(y.Tags) ||
+		cap(x.Tags) != cap(y.Tags) || (x.Clock == nil) != (y.Clock == nil) || len(x.Funcs) != len(y.Funcs) ||
+		!reflect.DeepEqual(x.Repo.DB.Config, y.Repo.DB.Config) || x.Repo.DB.Ready != y.Repo.DB.Ready || x.Repo.Cache == nil {
+		t.Fatalf("generated unexpected:\n%+v\n%+v", x, y)
+	}
+	if x.Name != "app" || !x.Repo.DB.Ready {
+		t.Fatalf("generated unexpected: %+v", x)
+	}
+
+	// the invoked Closer is owned by the caller like the reflection
+	if err = c.Close(ctx); err != nil || x.Repo.DB.closed || y.Repo.DB.closed {
+		t.Fatalf("generated close unexpected: %v", err)
+	}
+
+	// the same FieldError of the missing Provider
+	c = dix.NewContainer()
+	_, err = NewApp(ctx, c)
+	_, rerr := dix.Resolve[App](ctx, c)
+	fe, rfe := (*dix.FieldError)(nil), (*dix.FieldError)(nil)
+	if !errors.As(err, &fe) || !errors.As(rerr, &rfe) || fe.Error() != rfe.Error() || !errors.Is(err, dix.ErrNotBound) {
+		t.Fatalf("generated error unexpected:\n%v\n%v", err, rerr)
+	}
+}
@@ -0,0 +1,196 @@
+// Code generated by dixgen. DO NOT EDIT.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/silvacheung/dix"
+)
+
+// the dix tags of the fields, shared and read-only
+var (
+	dixTag0 = dix.NewTag("from:?")
+	dixTag1 = dix.NewTag("from:?;namespace:name")
+	dixTag2 = dix.NewTag("from:?;chan_buf:4")
+	dixTag3 = dix.NewTag("from:?;slice_len:1;slice_cap:3")
+	dixTag4 = dix.NewTag("from:?;namespace:timeout")
+	dixTag5 = dix.NewTag("from:?;map_size:8")
+	dixTag6 = dix.NewTag("from:config")
+)
+
+// NewApp is resolving App with the Container like dix.Resolve, the unbound App is created without reflection
+func NewApp(ctx context.Context, c *dix.Container) (x App, err error) {
+	if dix.Bound[App](c, dixTag0) {
+		return dix.ResolveTag[App](ctx, c, dixTag0)
+	}
+	var v App
+	if err = InjectApp(ctx, c, &v); err != nil {
+		return x, err
+	}
+	return v, nil
+}
+
+// InjectApp is injecting the zero value dix tag fields of x like Container.Inject without reflection
+func InjectApp(ctx context.Context, c *dix.Container, x *App) error {
+	var errs []error
+	if dix.IsZero(x.Repo) {
+		if dix.Bound[Repo](c, dixTag0) {
+			if v, err := dix.ResolveTag[Repo](ctx, c, dixTag0); err != nil {
+				errs = dix.AppendFieldError[App](errs, "Repo", "from:?", err)
+			} else {
+				x.Repo = v
+			}
+		} else {
+			var v Repo
+			if err := dixInjectRepo(ctx, c, &v); err != nil {
+				errs = dix.AppendFieldError[App](errs, "Repo", "from:?", err)
+			} else {
+				x.Repo = v
+			}
+		}
+	}
+	if x.Name == "" {
+		if dix.Bound[string](c, dixTag1) {
+			if v, err := dix.ResolveTag[string](ctx, c, dixTag1); err != nil {
+				errs = dix.AppendFieldError[App](errs, "Name", "from:?;namespace:name", err)
+			} else {
+				x.Name = v
+			}
+		}
+	}
+	if x.Events == nil {
+		if dix.Bound[chan string](c, dixTag2) {
+			if v, err := dix.ResolveTag[chan string](ctx, c, dixTag2); err != nil {
+				errs = dix.AppendFieldError[App](errs, "Events", "from:?;chan_buf:4", err)
+			} else {
+				x.Events = v
+			}
+		} else {
+			x.Events = make(chan string, 4)
+		}
+	}
+	if x.Tags == nil {
+		if dix.Bound[[]string](c, dixTag3) {
+			if v, err := dix.ResolveTag[[]string](ctx, c, dixTag3); err != nil {
+				errs = dix.AppendFieldError[App](errs, "Tags", "from:?;slice_len:1;slice_cap:3", err)
+			} else {
+				x.Tags = v
+			}
+		} else {
+			x.Tags = make([]string, 1, 3)
+		}
+	}
+	if x.Stringer == nil {
+		if dix.Bound[fmt.Stringer](c, dixTag0) {
+			if v, err := dix.ResolveTag[fmt.Stringer](ctx, c, dixTag0); err != nil {
+				errs = dix.AppendFieldError[App](errs, "Stringer", "from:?", err)
+			} else {
+				x.Stringer = v
+			}
+		}
+	}
+	if x.Clock == nil {
+		if dix.Bound[*time.Time](c, dixTag0) {
+			if v, err := dix.ResolveTag[*time.Time](ctx, c, dixTag0); err != nil {
+				errs = dix.AppendFieldError[App](errs, "Clock", "from:?", err)
+			} else {
+				x.Clock = v
+			}
+		} else {
+			v := new(time.Time)
+			x.Clock = v
+		}
+	}
+	if x.Preset == 0 {
+		if dix.Bound[int](c, dixTag0) {
+			if v, err := dix.ResolveTag[int](ctx, c, dixTag0); err != nil {
+				errs = dix.AppendFieldError[App](errs, "Preset", "from:?", err)
+			} else {
+				x.Preset = v
+			}
+		}
+	}
+	if x.Funcs == nil {
+		if dix.Bound[[]func()](c, dixTag0) {
+			if v, err := dix.ResolveTag[[]func()](ctx, c, dixTag0); err != nil {
+				errs = dix.AppendFieldError[App](errs, "Funcs", "from:?", err)
+			} else {
+				x.Funcs = v
+			}
+		} else {
+			x.Funcs = make([]func(), 0, 0)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// dixInjectRepo is injecting the zero value dix tag fields of x like Container.Inject without reflection
+func dixInjectRepo(ctx context.Context, c *dix.Container, x *Repo) error {
+	var errs []error
+	if x.DB == nil {
+		if dix.Bound[*DB](c, dixTag0) {
+			if v, err := dix.ResolveTag[*DB](ctx, c, dixTag0); err != nil {
+				errs = dix.AppendFieldError[Repo](errs, "DB", "from:?", err)
+			} else {
+				x.DB = v
+			}
+		} else {
+			v := new(DB)
+			if err := dixInjectDB(ctx, c, v); err != nil {
+				errs = dix.AppendFieldError[Repo](errs, "DB", "from:?", err)
+			} else {
+				x.DB = v
+			}
+		}
+	}
+	if x.Timeout == 0 {
+		if dix.Bound[time.Duration](c, dixTag4) {
+			if v, err := dix.ResolveTag[time.Duration](ctx, c, dixTag4); err != nil {
+				errs = dix.AppendFieldError[Repo](errs, "Timeout", "from:?;namespace:timeout", err)
+			} else {
+				x.Timeout = v
+			}
+		}
+	}
+	if x.Cache == nil {
+		if dix.Bound[map[string]int](c, dixTag5) {
+			if v, err := dix.ResolveTag[map[string]int](ctx, c, dixTag5); err != nil {
+				errs = dix.AppendFieldError[Repo](errs, "Cache", "from:?;map_size:8", err)
+			} else {
+				x.Cache = v
+			}
+		} else {
+			x.Cache = make(map[string]int, 8)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// dixInjectDB is injecting the zero value dix tag fields of x like Container.Inject without reflection
+func dixInjectDB(ctx context.Context, c *dix.Container, x *DB) error {
+	var errs []error
+	if x.Config == (Config{}) {
+		if v, err := dix.Provide[Config](ctx, c, dixTag6); err != nil {
+			errs = dix.AppendFieldError[DB](errs, "Config", "from:config", err)
+		} else {
+			x.Config = v
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	if err := x.Init(ctx); err != nil {
+		return fmt.Errorf("`*app.DB` init error: %w", err)
+	}
+	return nil
+}
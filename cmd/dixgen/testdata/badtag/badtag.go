@@ -0,0 +1,6 @@
+// Package badtag is the testing package of dixgen invalid dix tag
+package badtag
+
+type A struct {
+	Name string `dix:"from:'name"`
+}
@@ -0,0 +1,10 @@
+// Package cycle is the testing package of dixgen cycled dependency
+package cycle
+
+type A struct {
+	B *B `dix:"from:?"`
+}
+
+type B struct {
+	A A `dix:"from:?"`
+}
@@ -0,0 +1,76 @@
+// Command dixgraph renders the resolution graph of a Container registered in a test binary.
+//
+// The package under test registers the graph and exports it in a test:
+//
+//	func TestDixGraph(t *testing.T) {
+//		dix.RegisterGraph("app", c, reflect.TypeOf(App{}))
+//		if err := dix.ExportGraph(); err != nil {
+//			t.Fatal(err)
+//		}
+//	}
+//
+// Then dixgraph runs the test and writes the graph:
+//
+//	dixgraph -pkg ./internal/app -name app -format mermaid -o app.mmd
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/silvacheung/dix"
+)
+
+func main() {
+	pkg := flag.String("pkg", ".", "the package registered the graph")
+	run := flag.String("run", "TestDixGraph", "the test exported the graph")
+	name := flag.String("name", "", "the registered graph name")
+	format := flag.String("format", "dot", "the output format: dot, mermaid or json")
+	out := flag.String("o", "", "the output file, default is stdout")
+	flag.Parse()
+
+	if err := render(*pkg, *run, *name, *format, *out); err != nil {
+		fmt.Fprintln(os.Stderr, "dixgraph:", err)
+		os.Exit(1)
+	}
+}
+
+// render is running the test with the graph export environment and copying the graph to the output
+func render(pkg, run, name, format, out string) error {
+	dir, err := os.MkdirTemp("", "dixgraph")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "graph")
+	cmd := exec.Command("go", "test", "-count=1", "-run", "^"+run+"$", pkg)
+	cmd.Env = append(os.Environ(), dix.EnvGraphName+"="+name, dix.EnvGraphFormat+"="+format, dix.EnvGraphOut+"="+file)
+	cmd.Stdout, cmd.Stderr = os.Stderr, os.Stderr
+	if err = cmd.Run(); err != nil {
+		return fmt.Errorf("go test %s: %w", pkg, err)
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("graph `%s` is not exported by %s in %s: %w", name, run, pkg, err)
+	}
+	defer f.Close()
+
+	w := io.Writer(os.Stdout)
+	if out != "" {
+		o, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer o.Close()
+		w = o
+	}
+
+	_, err = io.Copy(w, f)
+	return err
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/silvacheung/dix"
+)
+
+func TestRender(t *testing.T) {
+	if testing.Short() {
+		t.Skip("go test of the graph package")
+	}
+
+	out := filepath.Join(t.TempDir(), "app.json")
+	if err := render("./testdata/app", "TestDixGraph", "app", "json", out); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var g dix.Graph
+	if err = json.Unmarshal(b, &g); err != nil {
+		t.Fatalf("graph json unexpected: %v\n%s", err, b)
+	}
+	pkg := "github.com/silvacheung/dix/cmd/dixgraph/testdata/app"
+	if !reflect.DeepEqual(g.Roots, []string{pkg + ".App"}) || len(g.Nodes) != 3 || len(g.Edges) != 2 {
+		t.Fatalf("graph unexpected:\n%s", b)
+	}
+	if g.Nodes[2].ID != pkg+".Config@def" || g.Nodes[2].Kind != dix.NodeBinding {
+		t.Fatalf("graph binding unexpected: %+v", g.Nodes[2])
+	}
+
+	// the graph not registered fails the test
+	if err = render("./testdata/app", "TestDixGraph", "none", "json", out); err == nil {
+		t.Fatal("render of the unregistered graph expected error")
+	}
+}
@@ -0,0 +1,26 @@
+package app
+
+import (
+	"reflect"
+
+	"github.com/silvacheung/dix"
+)
+
+type Config struct {
+	DSN string
+}
+
+type Repo struct {
+	Config Config `dix:"from:?"`
+}
+
+type App struct {
+	Repo *Repo `dix:"from:?"`
+}
+
+// Register is registering the graph of App as `app`
+func Register() {
+	c := dix.NewContainer()
+	dix.BindIn[Config](c, Config{DSN: "dsn"})
+	dix.RegisterGraph("app", c, reflect.TypeOf(App{}))
+}
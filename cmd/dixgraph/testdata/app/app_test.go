@@ -0,0 +1,14 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/silvacheung/dix"
+)
+
+func TestDixGraph(t *testing.T) {
+	Register()
+	if err := dix.ExportGraph(); err != nil {
+		t.Fatal(err)
+	}
+}
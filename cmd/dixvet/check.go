@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/silvacheung/dix"
+)
+
+// diagnostic is a problem of the dix tag at the position
+type diagnostic struct {
+	pos token.Position
+	msg string
+}
+
+func (d diagnostic) String() string {
+	return d.pos.String() + ": " + d.msg
+}
+
+// checker is checking the dix tags of the struct fields
+type checker struct {
+	keys map[string]bool // keys is the known tag keys, the builtin and the custom keys
+}
+
+func newChecker(custom []string) *checker {
+	c := &checker{keys: make(map[string]bool)}
+	for _, k := range []string{dix.TagSymbol, dix.TagNamespace, dix.TagChanBuf, dix.TagMapSize, dix.TagSliceLen, dix.TagSliceCap, dix.TagSecret} {
+		c.keys[k] = true
+	}
+	for _, k := range custom {
+		if k = strings.TrimSpace(k); k != "" {
+			c.keys[k] = true
+		}
+	}
+	return c
+}
+
+// checkDir is checking the Go files of the package directory, the test files included;
+// The package files are type checked for the field types, the type errors are ignored
+func (c *checker) checkDir(dir string) ([]diagnostic, error) {
+	bp, err := build.ImportDir(dir, build.ImportComment)
+	if err != nil {
+		if _, ok := err.(*build.NoGoError); ok {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	parse := func(names []string) ([]*ast.File, error) {
+		var files []*ast.File
+		for _, name := range names {
+			f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, f)
+		}
+		return files, nil
+	}
+
+	files, err := parse(append(append([]string(nil), bp.GoFiles...), bp.TestGoFiles...))
+	if err != nil {
+		return nil, err
+	}
+	xfiles, err := parse(bp.XTestGoFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &types.Info{Types: make(map[ast.Expr]types.TypeAndValue)}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil), Error: func(error) {}}
+	_, _ = conf.Check(bp.Name, fset, files, info)
+
+	var diags []diagnostic
+	for _, f := range append(files, xfiles...) {
+		diags = append(diags, c.checkFile(fset, info, f)...)
+	}
+	sort.SliceStable(diags, func(i, j int) bool {
+		a, b := diags[i].pos, diags[j].pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Offset < b.Offset
+	})
+	return diags, nil
+}
+
+// checkFile is checking the dix tags of all struct types of the file
+func (c *checker) checkFile(fset *token.FileSet, info *types.Info, f *ast.File) []diagnostic {
+	var diags []diagnostic
+	ast.Inspect(f, func(n ast.Node) bool {
+		st, ok := n.(*ast.StructType)
+		if !ok {
+			return true
+		}
+		for _, field := range st.Fields.List {
+			if field.Tag == nil {
+				continue
+			}
+			s, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				continue
+			}
+			val, ok := reflect.StructTag(s).Lookup(dix.TagDix)
+			if !ok {
+				continue
+			}
+
+			pos := fset.Position(field.Tag.Pos())
+			for _, msg := range c.check(val, field, info.TypeOf(field.Type)) {
+				diags = append(diags, diagnostic{pos: pos, msg: msg})
+			}
+		}
+		return true
+	})
+	return diags
+}
+
+// check return the problems of the dix tag value of the field, t is the field type or nil if unknown
+func (c *checker) check(val string, field *ast.Field, t types.Type) []string {
+	var msgs []string
+	report := func(format string, args ...any) {
+		msgs = append(msgs, fmt.Sprintf(format, args...))
+	}
+
+	names := field.Names
+	if len(names) == 0 {
+		// the embedded field is named by the type
+		x := field.Type
+		if star, ok := x.(*ast.StarExpr); ok {
+			x = star.X
+		}
+		if sel, ok := x.(*ast.SelectorExpr); ok {
+			x = sel.Sel
+		}
+		if id, ok := x.(*ast.Ident); ok {
+			names = []*ast.Ident{id}
+		}
+	}
+	for _, name := range names {
+		if !name.IsExported() {
+			report("dix tag on unexported field `%s` is ignored, inject cannot set it", name.Name)
+		}
+	}
+
+	// the pairs before the syntax error are checked too
+	pairs, err := dix.SplitTag(val)
+	if te, ok := err.(*dix.TagError); ok {
+		// the part of the error, like `slice_cap=3`
+		part := val[strings.LastIndex(val[:te.Offset], ";")+1:]
+		if i := strings.IndexByte(part, ';'); i >= 0 {
+			part = part[:i]
+		}
+		if k, v, ok := strings.Cut(strings.TrimSpace(part), "="); ok && c.keys[k] {
+			report("dix tag part `%s` uses `=`, want `%s:%s`", strings.TrimSpace(part), k, v)
+		} else {
+			report("dix tag syntax error at offset %d: %s", te.Offset, te.Msg)
+		}
+	}
+
+	ints := make(map[string]int)
+	seen := make(map[string]bool)
+	for _, p := range pairs {
+		k, v := p.Key, p.Value
+		if seen[k] {
+			report("dix tag key `%s` is repeated", k)
+		}
+		seen[k] = true
+
+		switch k {
+		case dix.TagChanBuf, dix.TagMapSize, dix.TagSliceLen, dix.TagSliceCap:
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				report("dix tag `%s` value `%s` is not a non-negative integer", k, v)
+				continue
+			}
+			ints[k] = n
+		case dix.TagSecret:
+			if _, err := strconv.ParseBool(v); err != nil {
+				report("dix tag `%s` value `%s` is not bool", k, v)
+			}
+		case dix.TagSymbol:
+			if v == "" {
+				report("dix tag `%s` value is empty", k)
+			}
+		default:
+			if !c.keys[k] {
+				report("dix tag key `%s` is unknown, allow the custom key by -keys", k)
+			}
+		}
+	}
+
+	if l, ok := ints[dix.TagSliceLen]; ok && l > ints[dix.TagSliceCap] {
+		report("dix tag `%s` %d is less than `%s` %d, make panics", dix.TagSliceCap, ints[dix.TagSliceCap], dix.TagSliceLen, l)
+	}
+
+	// the size keys only take effect on the invoked type of kind
+	if t != nil && t != types.Typ[types.Invalid] {
+		kinds := map[string]func(types.Type) bool{
+			dix.TagChanBuf:  func(u types.Type) bool { _, ok := u.(*types.Chan); return ok },
+			dix.TagMapSize:  func(u types.Type) bool { _, ok := u.(*types.Map); return ok },
+			dix.TagSliceLen: func(u types.Type) bool { _, ok := u.(*types.Slice); return ok },
+			dix.TagSliceCap: func(u types.Type) bool { _, ok := u.(*types.Slice); return ok },
+		}
+		for _, k := range []string{dix.TagChanBuf, dix.TagMapSize, dix.TagSliceLen, dix.TagSliceCap} {
+			if _, ok := ints[k]; ok && !kinds[k](t.Underlying()) {
+				report("dix tag `%s` has no effect on the field of type `%s`", k, t)
+			}
+		}
+	}
+
+	return msgs
+}
@@ -0,0 +1,61 @@
+// Command dixvet checks the dix struct tags of the packages, the mistakes found at runtime or silently ignored are reported.
+//
+//	dixvet ./...
+//	dixvet -keys kind,env ./internal/app
+//
+// It reports the syntax errors of dix.SplitTag (like the `slice_cap=3` typo), the repeated and unknown keys, the integer keys with
+// invalid values, the slice_cap less than slice_len, the keys having no effect on the field type and the tags on
+// the unexported fields which are never injected. The custom keys read by the Provider are allowed by -keys.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+func main() {
+	keys := flag.String("keys", "", "the comma separated custom tag keys read by the Provider")
+	flag.Parse()
+
+	patterns := flag.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	n, err := vet(patterns, strings.Split(*keys, ","))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "dixvet:", err)
+		os.Exit(2)
+	}
+	if n > 0 {
+		os.Exit(1)
+	}
+}
+
+// vet is checking the packages of the patterns, return the count of diagnostics
+func vet(patterns, keys []string) (int, error) {
+	out, err := exec.Command("go", append([]string{"list", "-f", "{{.Dir}}"}, patterns...)...).Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok {
+			return 0, fmt.Errorf("go list: %s", ee.Stderr)
+		}
+		return 0, err
+	}
+
+	c := newChecker(keys)
+	var n int
+	for _, dir := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		diags, err := c.checkDir(dir)
+		if err != nil {
+			return n, err
+		}
+		for _, d := range diags {
+			fmt.Fprintln(os.Stderr, d)
+		}
+		n += len(diags)
+	}
+	return n, nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestVet(t *testing.T) {
+	diags, err := newChecker([]string{"kind"}).checkDir("testdata/bad")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"11: dix tag part `slice_cap=3` uses `=`, want `slice_cap:3`",
+		"11: dix tag `slice_cap` 0 is less than `slice_len` 3, make panics",
+		"12: dix tag `slice_cap` 2 is less than `slice_len` 4, make panics",
+		"13: dix tag `chan_buf` value `ten` is not a non-negative integer",
+		"14: dix tag `map_size` value `-1` is not a non-negative integer",
+		"15: dix tag key `color` is unknown, allow the custom key by -keys",
+		"16: dix tag `chan_buf` has no effect on the field of type `int`",
+		"17: dix tag `secret` value `yes` is not bool",
+		"18: dix tag key `from` is repeated",
+		"19: dix tag `from` value is empty",
+		"20: dix tag syntax error at offset 16: missing `:` after key `namespace`",
+		"21: dix tag on unexported field `private` is ignored, inject cannot set it",
+		"24: dix tag syntax error at offset 17: unterminated quoted value",
+		"25: dix tag syntax error at offset 19: invalid escape, want `\\'` or `\\\\`",
+		"26: dix tag syntax error at offset 19: quote in raw value, quote the whole value",
+	}
+	if len(diags) != len(want) {
+		t.Fatalf("diagnostics unexpected: %v", diags)
+	}
+	for i, d := range diags {
+		if got := strconv.Itoa(d.pos.Line) + ": " + d.msg; got != want[i] {
+			t.Fatalf("diagnostic %d unexpected:\n got: %s\nwant: %s", i, got, want[i])
+		}
+	}
+}
@@ -0,0 +1,28 @@
+// Package bad is the testing package of dixvet
+package bad
+
+import "context"
+
+type Config struct {
+	DSN string
+}
+
+type Bad struct {
+	Typo     []string              `dix:"from:?;slice_len:3;slice_cap=3"`
+	Cap      []string              `dix:"from:?;slice_len:4;slice_cap:2"`
+	Buf      chan int              `dix:"from:?;chan_buf:ten"`
+	Size     map[string]int        `dix:"from:?;map_size:-1"`
+	Unknown  int                   `dix:"from:?;kind:x1;color:red"`
+	NoEffect int                   `dix:"from:?;chan_buf:1"`
+	Secret   string                `dix:"from:?;secret:yes"`
+	Repeated int                   `dix:"from:?;from:x"`
+	Empty    Config                `dix:"from:"`
+	NoValue  int                   `dix:"from:?;namespace"`
+	private  int                   `dix:"from:?"`
+	Good     []string              `dix:"from:?;slice_len:1;slice_cap:3;namespace:ns1"`
+	Func     func(context.Context) `dix:"from:?"`
+	Quoted   string                `dix:"from:?;namespace:'a;b"`
+	Escaped  string                `dix:"from:?;namespace:'a\\;b'"`
+	Raw      string                `dix:"from:?;namespace:it's"`
+	Good2    string                `dix:"from:?; namespace: 'a;b' ;kind:x"`
+}
@@ -0,0 +1,75 @@
+package dix
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+var typeError = reflect.TypeOf((*error)(nil)).Elem()
+
+// Constructor is binding the constructor function in the default Container, see Container.Constructor
+func Constructor(fn any, opts ...Option) error {
+	return defContainer.Constructor(fn, opts...)
+}
+
+// Constructor is binding the constructor function, fn is like `func(cfg Config, log *Logger) (*Repo, error)`;
+// The first return type is the binding type, the optional second return must be error;
+// Each parameter is resolved by di with the tag of WithParamTags, if not set use `from:?` in the default namespace
+func (c *Container) Constructor(fn any, opts ...Option) error {
+	f := reflect.ValueOf(fn)
+	if f.Kind() != reflect.Func || f.IsNil() {
+		return fmt.Errorf("constructor `%T` is not a function", fn)
+	}
+
+	ft := f.Type()
+	switch {
+	case ft.NumOut() == 0 || ft.NumOut() > 2:
+		return fmt.Errorf("constructor `%s` must return (T) or (T, error)", ft)
+	case ft.NumOut() == 2 && ft.Out(1) != typeError:
+		return fmt.Errorf("constructor `%s` second return must be error", ft)
+	}
+
+	o := newOptions(opts...)
+	if len(o.params) > ft.NumIn() {
+		return fmt.Errorf("constructor `%s` has %d params but %d param tags", ft, ft.NumIn(), len(o.params))
+	}
+	for i, val := range o.params {
+		if _, e := ParseTag(val); e != nil {
+			return fmt.Errorf("constructor `%s` param %d: %w", ft, i, e)
+		}
+	}
+
+	e := ft.Out(0)
+	c.bind(e, &ref{t: e, v: reflect.Zero(e), fn: f, params: o.params, life: o.lifetime}, o.namespaces...)
+	c.logBinding(e, f, o.lifetime, o.secret, o.namespaces...)
+
+	return nil
+}
+
+// construct is calling the constructor with the parameters resolved by di
+func (c *Container) construct(ctx context.Context, rs *resolver, r *ref) (v reflect.Value, e error) {
+	// check is cycled dependency, the parameters are resolved on the path of the constructor type
+	if e = rs.cycled(r.t); e != nil {
+		return
+	}
+
+	ft := r.fn.Type()
+	in, e := c.args(ctx, rs, r.t, ft, r.params)
+	if e != nil {
+		return v, fmt.Errorf("constructor %w", e)
+	}
+
+	out := call(r.fn, in)
+	if len(out) == 2 && !out[1].IsNil() {
+		return v, fmt.Errorf("constructor `%s` error: %w", ft, out[1].Interface().(error))
+	}
+
+	// the struct result is not addressable, copy it so the fields can be injected
+	if v = out[0]; v.Kind() == reflect.Struct {
+		v = reflect.New(r.t).Elem()
+		v.Set(out[0])
+	}
+
+	return v, nil
+}
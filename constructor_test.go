@@ -0,0 +1,79 @@
+package dix
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type TConfig struct {
+	DSN string
+}
+
+type TRepo struct {
+	Config TConfig
+	TB     *TB `dix:"from:?"`
+}
+
+func TestConstructor(t *testing.T) {
+	ctx := context.Background()
+
+	calls := 0
+	c := NewContainer()
+	BindIn[TConfig](c, TConfig{DSN: "def"})
+	BindIn[TConfig](c, TConfig{DSN: "ns1"}, "ns1")
+	if err := c.Constructor(func(cfg TConfig) (*TRepo, error) {
+		calls++
+		return &TRepo{Config: cfg}, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Constructor(func(cfg TConfig) *TRepo {
+		return &TRepo{Config: cfg}
+	}, WithNamespace("ns1"), WithParamTags("namespace:ns1"), WithLifetime(Transient)); err != nil {
+		t.Fatal(err)
+	}
+
+	type X struct {
+		Repo1 *TRepo `dix:"from:?"`
+		Repo2 *TRepo `dix:"from:?"`
+		Repo3 *TRepo `dix:"from:?;namespace:ns1"`
+	}
+
+	x := MustResolve[X](ctx, c)
+	if calls != 1 || x.Repo1 != x.Repo2 || x.Repo1.Config.DSN != "def" || x.Repo1.TB == nil {
+		t.Fatalf("singleton constructor unexpected: %d %+v", calls, x.Repo1)
+	}
+	if x.Repo3.Config.DSN != "ns1" || x.Repo3.TB == nil {
+		t.Fatalf("transient constructor unexpected: %+v", x.Repo3)
+	}
+
+	errCtor := errors.New("ctor error")
+	if err := c.Constructor(func() (*TRepo, error) { return nil, errCtor }, WithNamespace("err")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Resolve[X](ctx, c.Child()); err != nil {
+		t.Fatal(err)
+	}
+	type Y struct {
+		Repo *TRepo `dix:"from:?;namespace:err"`
+	}
+	if _, err := Resolve[Y](ctx, c); !errors.Is(err, errCtor) {
+		t.Fatalf("constructor error unexpected: %v", err)
+	}
+
+	if err := c.Constructor(func() (*TRepo, int) { return nil, 0 }); err == nil {
+		t.Fatal("constructor second return must be error")
+	}
+
+	// the struct value result is injected too, every lifetime
+	for _, l := range []Lifetime{Singleton, Transient, Scoped} {
+		c := NewContainer()
+		if err := c.Constructor(func() TRepo { return TRepo{Config: TConfig{DSN: "value"}} }, WithLifetime(l)); err != nil {
+			t.Fatal(err)
+		}
+		if repo, err := Resolve[TRepo](ctx, c); err != nil || repo.Config.DSN != "value" || repo.TB == nil {
+			t.Fatalf("%s struct constructor unexpected: %+v, %v", l, repo, err)
+		}
+	}
+}
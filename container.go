@@ -0,0 +1,266 @@
+package dix
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// defContainer is the default container used by the top-level functions
+var defContainer = NewContainer()
+
+// Container is the dependency injection container,
+// it owns the Provider registry, the type binding registry and the struct fields cache;
+// A Container is safe for concurrent use, the registries are copy-on-write snapshots so resolving never locks the registries
+type Container struct {
+	parent   *Container                   // parent is the fallback Container of lookup, nil is root
+	mu       sync.Mutex                   // mu serializes the registry writers
+	reg      atomic.Pointer[registry]     // reg is the current registry snapshot
+	scoped   sync.Map                     // scoped is map[*ref]*instance, the Scoped instances of this Container
+	omu      sync.Mutex                   // omu is guarded the owned and services
+	smu      sync.Mutex                   // smu serializes the Start and Stop, guarded the started
+	owned    []any                        // owned is the Closer instances, in creation order
+	services []Service                    // services is the Service instances, in creation order
+	started  int                          // started is the count of services started
+	lazy     atomic.Bool                  // lazy is the pointer Singleton allocated before wiring
+	log      atomic.Pointer[slog.Logger]  // log is the debug logger, nil is fallback to the parent
+	redact   atomic.Pointer[RedactPolicy] // redact is the RedactPolicy, nil is fallback to the parent
+	trace    atomic.Pointer[Tracer]       // trace is the Tracer, nil is fallback to the parent
+}
+
+// registry is an immutable snapshot of the Provider and type binding registries
+type registry struct {
+	provider map[string]map[string]Provider
+	binding  map[reflect.Type]map[string]*ref
+}
+
+// NewContainer create an empty Container
+func NewContainer() *Container {
+	c := &Container{}
+	c.reg.Store(&registry{
+		provider: make(map[string]map[string]Provider),
+		binding:  make(map[reflect.Type]map[string]*ref),
+	})
+	return c
+}
+
+// Child create a child Container of c, the child can add or override Provider and type binding,
+// and fallback to the parent for everything else; the child never writes the parent
+func (c *Container) Child() *Container {
+	child := NewContainer()
+	child.parent = c
+	return child
+}
+
+// SetLazyWiring set the pointer Singleton binding is wired lazily, the binding pointer is used before its fields are injected,
+// so the cycles through pointer Singleton are resolved rather than rejected; the value type cycles are still error;
+// The concurrent DI meeting the cycle in progress by another goroutine use the pointer too, and wait for its wiring
+// before returning, so the Initializer must not read the lazily wired pointer
+func (c *Container) SetLazyWiring(ok bool) *Container {
+	c.lazy.Store(ok)
+	return c
+}
+
+// SetLogger set the logger of the Container, the resolution information is logged at debug level;
+// If not set (or set nil) the child Container use the logger of parent, the root Container not log
+func (c *Container) SetLogger(l *slog.Logger) *Container {
+	c.log.Store(l)
+	return c
+}
+
+// logger return the logger of the Container, walk the chain child to parent
+func (c *Container) logger() *slog.Logger {
+	for x := c; x != nil; x = x.parent {
+		if l := x.log.Load(); l != nil {
+			return l
+		}
+	}
+	return nil
+}
+
+// Parent return the parent Container, the root Container return nil
+func (c *Container) Parent() *Container { return c.parent }
+
+// lookupProvider is take the Provider of symbol in the namespace, walk the chain child to parent
+func (c *Container) lookupProvider(symbol, namespace string) Provider {
+	for x := c; x != nil; x = x.parent {
+		if provider, ok := x.reg.Load().provider[symbol][namespace]; ok && provider != nil {
+			return provider
+		}
+	}
+	return nil
+}
+
+// lookupBinding is take the binding of type in the namespace and its owner, walk the chain child to parent
+func (c *Container) lookupBinding(t reflect.Type, namespace string) (*ref, *Container) {
+	for x := c; x != nil; x = x.parent {
+		if r, ok := x.reg.Load().binding[t][namespace]; ok {
+			return r, x
+		}
+	}
+	return nil, nil
+}
+
+// Default return the default Container used by Binding, DI and MustDI
+func Default() *Container { return defContainer }
+
+// update is copy the current registry snapshot, apply fn to the copy and publish it
+func (c *Container) update(fn func(reg *registry)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	old := c.reg.Load()
+	reg := &registry{
+		provider: make(map[string]map[string]Provider, len(old.provider)+1),
+		binding:  make(map[reflect.Type]map[string]*ref, len(old.binding)+1),
+	}
+	for k, v := range old.provider {
+		reg.provider[k] = v
+	}
+	for k, v := range old.binding {
+		reg.binding[k] = v
+	}
+
+	fn(reg)
+	c.reg.Store(reg)
+}
+
+// bind is binding write with namespaces, all namespaces share the same ref
+func (c *Container) bind(e reflect.Type, r *ref, namespaces ...string) {
+	c.update(func(reg *registry) {
+		n := make(map[string]*ref, len(reg.binding[e])+len(namespaces))
+		for namespace, r := range reg.binding[e] {
+			n[namespace] = r
+		}
+		for _, namespace := range namespaces {
+			n[namespace] = r
+		}
+		reg.binding[e] = n
+	})
+}
+
+// BindIn is binding Provider and other type in the Container, create inject type mapping with namespaces;
+// If you want to set custom values and also want to automatically inject zero values, please use exportable fields
+func BindIn[X any](c *Container, x X, namespaces ...string) {
+	BindWith[X](c, x, WithNamespace(namespaces...))
+}
+
+// BindWith is binding Provider and other type in the Container with options, see WithNamespace and WithLifetime;
+// The Provider binding only use the namespaces option, the Provider itself decide the lifetime of values
+func BindWith[X any](c *Container, x X, opts ...Option) {
+	o := newOptions(opts...)
+	namespaces := o.namespaces
+
+	var i interface{} = x
+	switch ix := i.(type) {
+	case Provider:
+		c.update(func(reg *registry) {
+			n := make(map[string]Provider, len(reg.provider[ix.Symbol()])+len(namespaces))
+			for namespace, p := range reg.provider[ix.Symbol()] {
+				n[namespace] = p
+			}
+			for _, namespace := range namespaces {
+				n[namespace] = ix
+			}
+			reg.provider[ix.Symbol()] = n
+		})
+		c.logProvider(ix, namespaces...)
+	default:
+		e := reflect.TypeOf(&x).Elem()
+		t := reflect.TypeOf(x)
+		v := reflect.ValueOf(x)
+
+		// if struct must convert to addressable reflect.Value
+		if t.Kind() == reflect.Struct {
+			if e.Kind() == reflect.Interface {
+				n := reflect.New(t).Elem()
+				c := n.NumField()
+				for i := 0; i < c; i++ {
+					if f := n.Field(i); f.CanSet() {
+						f.Set(v.Field(i))
+					}
+				}
+				v = n
+			} else {
+				v = reflect.ValueOf(&x).Elem()
+			}
+		}
+
+		c.bind(e, &ref{t: t, v: v, life: o.lifetime}, namespaces...)
+		c.logBinding(e, v, o.lifetime, o.secret, namespaces...)
+	}
+}
+
+// MustResolve is Resolve wrapped, if happen error will panic
+func MustResolve[X any](ctx context.Context, c *Container) X {
+	x, e := Resolve[X](ctx, c)
+	if e != nil {
+		panic(e)
+	}
+	return x
+}
+
+// Resolve is dependency injection method with the Container,
+// Cannot dependency on oneself, otherwise circular dependency will occur
+func Resolve[X any](ctx context.Context, c *Container) (x X, e error) {
+	tag := NewTag().SetSymbol(TagInvoke)
+	defer tag.Free()
+
+	return ResolveTag[X](ctx, c, tag)
+}
+
+// ResolveTag is Resolve with the dix tag, like the field `dix:"from:?;namespace:ns1"`
+func ResolveTag[X any](ctx context.Context, c *Container, tag *Tag) (x X, e error) {
+	rs := newResolver(ctx)
+	defer rs.release()
+
+	t := reflect.TypeOf((*X)(nil)).Elem()
+	v, e := c.di(ctx, rs, t, tag)
+	if e == nil {
+		e = rs.settle()
+	}
+	if e == nil {
+		x = assign[X](v)
+	}
+
+	return x, e
+}
+
+// assign return the resolved value as X, the addressable value of X is copied without boxing
+func assign[X any](v reflect.Value) (x X) {
+	switch {
+	case !v.IsValid():
+	case v.CanAddr() && v.Type() == reflect.TypeOf((*X)(nil)).Elem():
+		x = *(*X)(v.Addr().UnsafePointer())
+	default:
+		x, _ = v.Interface().(X)
+	}
+	return x
+}
+
+// Inject is populating the caller-owned struct in place, x must be a non-nil pointer to struct;
+// Same as DI, only the zero value fields with dix tag are injected
+func (c *Container) Inject(ctx context.Context, x any) error {
+	return c.populate(ctx, x, false)
+}
+
+// populate is Inject, the Container owns the Transient and invoked instances if own
+func (c *Container) populate(ctx context.Context, x any, own bool) error {
+	v := reflect.ValueOf(x)
+	if v.Kind() != reflect.Pointer || v.IsNil() || deref(v.Type()).Kind() != reflect.Struct {
+		return fmt.Errorf("inject `%T` is not a non-nil pointer to struct", x)
+	}
+
+	rs := newResolver(ctx)
+	defer rs.release()
+
+	rs.own = own
+
+	if e := c.fill(ctx, rs, v); e != nil {
+		return e
+	}
+	return rs.settle()
+}
@@ -0,0 +1,137 @@
+package dix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestContainer(t *testing.T) {
+	c1, c2 := NewContainer(), NewContainer()
+	BindIn[int](c1, 1)
+	BindIn[int](c2, 2, "ns2")
+	BindIn[Provider](c2, TBProvider{})
+
+	type X struct {
+		Int   int `dix:"from:?"`
+		IntNs int `dix:"from:?;namespace:ns2"`
+		TB    TB  `dix:"from:TBP"`
+	}
+
+	_, err := Resolve[X](context.Background(), c1)
+	if pe := (*ProviderError)(nil); !errors.As(err, &pe) || pe.Symbol != "TBP" || !errors.Is(err, ErrNotBound) {
+		t.Fatalf("c1 resolve unexpected: %v", err)
+	}
+
+	BindIn[Provider](c1, TBProvider{})
+	x1 := MustResolve[X](context.Background(), c1)
+	if x1.Int != 1 || x1.IntNs != 0 || x1.TB.Int8 != 100 {
+		t.Fatalf("c1 resolve unexpected: %+v", x1)
+	}
+
+	x2 := MustResolve[X](context.Background(), c2)
+	if x2.Int != 0 || x2.IntNs != 2 || x2.TB.Int8 != 100 {
+		t.Fatalf("c2 resolve unexpected: %+v", x2)
+	}
+}
+
+func TestContainerConcurrent(t *testing.T) {
+	c := NewContainer()
+	BindIn[Provider](c, TBProvider{})
+	BindIn[*TB](c, &TB{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			BindIn[int](c, i, "ns"+strconv.Itoa(i))
+		}(i)
+		go func() {
+			defer wg.Done()
+			if _, err := Resolve[TA](context.Background(), c); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestContainerChild(t *testing.T) {
+	parent := NewContainer()
+	BindIn[int](parent, 1)
+	BindIn[string](parent, "parent")
+
+	child := parent.Child()
+	BindIn[int](child, 2)
+	BindIn[Provider](child, TBProvider{})
+
+	type X struct {
+		Int    int    `dix:"from:?"`
+		String string `dix:"from:?"`
+		TB     TB     `dix:"from:TBP"`
+	}
+
+	x := MustResolve[X](context.Background(), child)
+	if x.Int != 2 || x.String != "parent" || x.TB.Int8 != 100 {
+		t.Fatalf("child resolve unexpected: %+v", x)
+	}
+
+	if _, err := Resolve[X](context.Background(), parent); !errors.Is(err, ErrNotBound) {
+		t.Fatalf("parent resolve unexpected: %v", err)
+	}
+}
+
+func TestContainerInject(t *testing.T) {
+	c := NewContainer()
+	BindIn[int](c, 1)
+	BindIn[Provider](c, TBProvider{})
+
+	type X struct {
+		Int    int `dix:"from:?"`
+		Custom int `dix:"from:?"`
+		TB     TB  `dix:"from:TBP"`
+		Skip   int
+	}
+
+	x := &X{Custom: 100}
+	if err := c.Inject(context.Background(), x); err != nil {
+		t.Fatal(err)
+	}
+	if x.Int != 1 || x.Custom != 100 || x.TB.Int8 != 100 || x.Skip != 0 {
+		t.Fatalf("inject unexpected: %+v", x)
+	}
+
+	if err := c.Inject(context.Background(), X{}); err == nil {
+		t.Fatal("inject must be pointer to struct")
+	}
+}
+
+func TestContainerLogger(t *testing.T) {
+	var buf bytes.Buffer
+	parent := NewContainer().SetLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	child := parent.Child()
+	BindIn[int](child, 1, "ns1")
+
+	type X struct {
+		Int int `dix:"from:?;namespace:ns1"`
+	}
+	MustResolve[X](context.Background(), child)
+
+	var binding, inject map[string]any
+	dec := json.NewDecoder(&buf)
+	if err := dec.Decode(&binding); err != nil || binding["msg"] != "dix type binding" || binding["type"] != "int" {
+		t.Fatalf("binding log unexpected: %v %v", err, binding)
+	}
+	if err := dec.Decode(&inject); err != nil || inject["field"] != "Int" || inject["namespace"] != "ns1" || inject["symbol"] != "?" {
+		t.Fatalf("inject log unexpected: %v %v", err, inject)
+	}
+	if _, ok := inject["duration"]; !ok {
+		t.Fatalf("inject log unexpected: %v", inject)
+	}
+}
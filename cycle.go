@@ -0,0 +1,253 @@
+package dix
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+var poolResolver = &sync.Pool{New: func() any { r := &resolver{}; r.path = r.stack[:0]; return r }}
+
+// ctxKeyResolver is the context key of the outer resolution passed to Provide, the Provider may DI with ctx again
+type ctxKeyResolver struct{}
+
+// outer is the resolution state passed to Provide by ctx, it is immutable
+type outer struct {
+	path []frame // path is a copy of the resolution path
+	w    *waiter // w is the waiter of the resolver calling Provide
+}
+
+// waits is guarding the wait graph, the waiter.waiting of all resolvers
+var waits sync.Mutex
+
+// resolver is the resolution state of a DI call, it is threaded through di and inject so the user ctx is untouched;
+// A resolver is used by one goroutine, the nested DI of a Provider use a new resolver continuing the outer path
+type resolver struct {
+	base  []frame      // base is the immutable outer path of the Provider DI with ctx
+	path  []frame      // path is the resolution path stack
+	ex    *Explanation // ex is the Explanation being resolved, nil if not explaining
+	w     *waiter      // w is the node of the wait graph, allocated on the first need
+	up    *waiter      // up is the waiter of the outer resolver calling Provide, nil if not nested
+	own   bool         // own is the Container owns the Transient and invoked instances, see Container.Run
+	stack [16]frame    // stack is the initial storage of path
+}
+
+// waiter is the node of a resolver in the wait graph, it is not pooled so it outlives the resolver;
+// The outer resolver (up) is blocked until the nested DI of its Provider returns
+type waiter struct {
+	up       *waiter   // up is the waiter of the outer resolver
+	waiting  *flight   // waiting is the flight waited by it or a nested waiter, guarded by waits
+	borrowed []*flight // borrowed is the flights of the lazy pointers used before wired, guarded by waits
+}
+
+// flight is the resolution in progress of a Singleton or Scoped instance, the other resolvers wait for done
+type flight struct {
+	owner *waiter       // owner is the waiter of the resolver resolving the instance
+	done  chan struct{} // done is closed when the resolution is finished
+	v     reflect.Value // v is the resolved value, read after done
+	e     error         // e is the resolution error, read after done
+}
+
+// frame is a step of the resolution path, a struct field, a constructor parameter or a Provider
+type frame struct {
+	t         reflect.Type // t is the struct or constructor type, nil is Provider
+	field     string       // field is the struct field name, empty is the constructor parameter
+	param     int          // param is the constructor parameter index, it is not a string so the push is allocation-free
+	symbol    string       // symbol is the Provider symbol
+	namespace string       // namespace is the Provider namespace
+	inst      *instance    // inst is the lazy Singleton being wired, the frame is a marker not a step
+}
+
+// String return the frame name like `pkg.A.f` or `provider(symbol, namespace)`
+func (f *frame) String() string {
+	if f.t == nil {
+		return "provider(" + f.symbol + ", " + f.namespace + ")"
+	}
+	if f.field == "" {
+		return f.t.String() + ".param" + strconv.Itoa(f.param)
+	}
+	return f.t.String() + "." + f.field
+}
+
+// newResolver return a resolver continuing the path passed to Provide by ctx, release it after the DI
+func newResolver(ctx context.Context) *resolver {
+	r := poolResolver.Get().(*resolver)
+	if o, ok := ctx.Value(ctxKeyResolver{}).(*outer); ok {
+		r.base, r.up = o.path, o.w
+	}
+	return r
+}
+
+// release is putting the resolver back to the pool
+func (r *resolver) release() {
+	clear(r.path[:cap(r.path)])
+	r.base, r.path, r.ex, r.w, r.up, r.own = nil, r.stack[:0], nil, nil, nil, false
+	poolResolver.Put(r)
+}
+
+// push is pushing the frame on the resolution path
+func (r *resolver) push(f frame) {
+	r.path = append(r.path, f)
+}
+
+// pop is popping the last frame of the resolution path, the frame is cleared by release
+func (r *resolver) pop() {
+	r.path = r.path[:len(r.path)-1]
+}
+
+// len return the length of the resolution path, the outer path included
+func (r *resolver) len() int {
+	return len(r.base) + len(r.path)
+}
+
+// at return the frame i of the resolution path, the outer path first
+func (r *resolver) at(i int) *frame {
+	if i < len(r.base) {
+		return &r.base[i]
+	}
+	return &r.path[i-len(r.base)]
+}
+
+// provide return ctx passed to Provide, it carries a copy of the resolution path and the waiter
+func (r *resolver) provide(ctx context.Context) context.Context {
+	var o *outer
+	if r.w == nil {
+		// the waiter is allocated with the first outer
+		x := &struct {
+			outer
+			waiter
+		}{waiter: waiter{up: r.up}}
+		o, r.w = &x.outer, &x.waiter
+	} else {
+		o = &outer{}
+	}
+	o.w = r.w
+	o.path = append(append(make([]frame, 0, r.len()), r.base...), r.path...)
+	return context.WithValue(ctx, ctxKeyResolver{}, o)
+}
+
+// waiter return the node of the resolver in the wait graph
+func (r *resolver) waiter() *waiter {
+	if r.w == nil {
+		r.w = &waiter{up: r.up}
+	}
+	return r.w
+}
+
+// await is waiting the flight of other resolver done and return its result;
+// The waiting would deadlock if the owner of the flight is this resolver or an outer one, or the owner is waiting
+// transitively the flight of them, by the nested DI of its Provider too; then the lazy pointer is borrowed if borrow,
+// otherwise the error is CycleError;
+// The path of other goroutines is not known
+func (r *resolver) await(f *flight, t reflect.Type, borrow bool) (v reflect.Value, borrowed bool, e error) {
+	w := r.waiter()
+
+	waits.Lock()
+	for o := f.owner; ; o = o.waiting.owner {
+		if w.blocks(o) {
+			if borrow {
+				w.borrowed = append(w.borrowed, f)
+			}
+			waits.Unlock()
+			if borrow {
+				return reflect.Value{}, true, nil
+			}
+			return reflect.Value{}, false, r.cycleError(0, t.String())
+		}
+		if o.waiting == nil {
+			break
+		}
+	}
+	// the outer waiters blocked in Provide are waiting the flight too, so the walk of other resolvers sees it
+	for x := w; x != nil && x.waiting == nil; x = x.up {
+		x.waiting = f
+	}
+	waits.Unlock()
+
+	<-f.done
+
+	waits.Lock()
+	for x := w; x != nil && x.waiting == f; x = x.up {
+		x.waiting = nil
+	}
+	waits.Unlock()
+
+	return f.v, false, f.e
+}
+
+// settle is waiting the flights of the borrowed lazy pointers before the DI returns, so the pointers are wired then;
+// The nested DI of a Provider hands them over to the outer resolver, it is blocked until the nested DI returns
+func (r *resolver) settle() error {
+	if r.w == nil {
+		return nil
+	}
+
+	waits.Lock()
+	borrowed := r.w.borrowed
+	r.w.borrowed = nil
+	if r.up != nil {
+		r.up.borrowed = append(r.up.borrowed, borrowed...)
+		borrowed = nil
+	}
+	waits.Unlock()
+
+	for _, f := range borrowed {
+		if <-f.done; f.e != nil {
+			return f.e
+		}
+	}
+	return nil
+}
+
+// blocks is checked o is blocked until w returns, o is w or an outer waiter of w
+func (w *waiter) blocks(o *waiter) bool {
+	for x := w; x != nil; x = x.up {
+		if x == o {
+			return true
+		}
+	}
+	return false
+}
+
+// cycled is checked the type recurrence on the resolution path, pointer types are compared by the real type
+func (r *resolver) cycled(t reflect.Type) error {
+	e := deref(t)
+	for i := r.len() - 1; i >= 0; i-- {
+		if f := r.at(i); f.t != nil && f.inst == nil && deref(f.t) == e {
+			return r.cycleError(i, t.String())
+		}
+	}
+	return nil
+}
+
+// wiring is checked the lazy Singleton instance being wired on the resolution path
+func (r *resolver) wiring(inst *instance) bool {
+	for i := r.len() - 1; i >= 0; i-- {
+		if r.at(i).inst == inst {
+			return true
+		}
+	}
+	return false
+}
+
+// cycledProvider is checked the Provider recurrence on the resolution path
+func (r *resolver) cycledProvider(symbol, namespace string) error {
+	for i := r.len() - 1; i >= 0; i-- {
+		if f := r.at(i); f.t == nil && f.inst == nil && f.symbol == symbol && f.namespace == namespace {
+			return r.cycleError(i, (&frame{symbol: symbol, namespace: namespace}).String())
+		}
+	}
+	return nil
+}
+
+// cycleError make the CycleError with the path from the first recurrence i to the leaf, then the recurrence
+func (r *resolver) cycleError(i int, recurrence string) *CycleError {
+	var path []string
+	for ; i < r.len(); i++ {
+		if f := r.at(i); f.inst == nil {
+			path = append(path, f.String())
+		}
+	}
+	return &CycleError{Path: append(path, recurrence)}
+}
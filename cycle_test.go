@@ -0,0 +1,400 @@
+package dix
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type TCycleX struct {
+	Int int `dix:"from:?"`
+}
+
+type TCycleXY struct {
+	X TCycleX `dix:"from:?"`
+}
+
+type TCycleProvider struct {
+	c *Container
+}
+
+func (TCycleProvider) Symbol() string {
+	return "cycle"
+}
+
+func (p TCycleProvider) Provide(ctx context.Context, tag *Tag) (any, error) {
+	type X struct {
+		Int int `dix:"from:cycle"`
+	}
+	x, err := Resolve[X](ctx, p.c)
+	return x.Int, err
+}
+
+func TestCycled(t *testing.T) {
+	ctx := context.Background()
+
+	// the prefix name is not a cycle
+	if _, err := DI[TCycleXY](ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// the full path with field names
+	_, err := DI[TCycleA](ctx)
+	ce := (*CycleError)(nil)
+	want := []string{"dix.TCycleA.B", "dix.TCycleB.A", "dix.TCycleA"}
+	if !errors.As(err, &ce) || !reflect.DeepEqual(ce.Path, want) {
+		t.Fatalf("cycle error unexpected: %v", err)
+	}
+
+	// the anonymous struct type
+	type Y struct {
+		Anonymous struct {
+			Y *Y `dix:"from:?"`
+		} `dix:"from:?"`
+	}
+	if _, err = DI[Y](ctx); !errors.As(err, &ce) || len(ce.Path) != 3 {
+		t.Fatalf("anonymous cycle error unexpected: %v", err)
+	}
+
+	// the provider to provider recursion
+	c := NewContainer()
+	BindIn[Provider](c, TCycleProvider{c: c})
+	type Z struct {
+		Int int `dix:"from:cycle"`
+	}
+	if _, err = Resolve[Z](ctx, c); !errors.As(err, &ce) || ce.Path[len(ce.Path)-1] != "provider(cycle, def)" {
+		t.Fatalf("provider cycle error unexpected: %v", err)
+	}
+
+	// the constructor recursion
+	c = NewContainer()
+	_ = c.Constructor(func(b *TCycleB) *TCycleA { return &TCycleA{B: b} })
+	_ = c.Constructor(func(a *TCycleA) *TCycleB { return &TCycleB{A: a} })
+	want = []string{"*dix.TCycleA.param0", "*dix.TCycleB.param0", "*dix.TCycleA"}
+	if _, err = Resolve[*TCycleA](ctx, c); !errors.As(err, &ce) || !reflect.DeepEqual(ce.Path, want) {
+		t.Fatalf("constructor cycle error unexpected: %v", err)
+	}
+}
+
+type TRaceP struct {
+	Barrier string  `dix:"from:barrier"`
+	C       *TRaceC `dix:"from:?"`
+}
+
+type TRaceC struct {
+	Barrier string  `dix:"from:barrier"`
+	P       *TRaceP `dix:"from:?"`
+}
+
+// TBarrierProvider is blocking the first n Provide until all of them arrive, so the resolutions are in progress together
+type TBarrierProvider struct {
+	wg *sync.WaitGroup
+	n  *atomic.Int32
+}
+
+func NewTBarrierProvider(n int) TBarrierProvider {
+	p := TBarrierProvider{wg: &sync.WaitGroup{}, n: &atomic.Int32{}}
+	p.wg.Add(n)
+	p.n.Store(int32(n))
+	return p
+}
+
+func (TBarrierProvider) Symbol() string {
+	return "barrier"
+}
+
+func (p TBarrierProvider) Provide(ctx context.Context, tag *Tag) (any, error) {
+	if p.n.Add(-1) >= 0 {
+		p.wg.Done()
+	}
+	p.wg.Wait()
+	return "barrier", nil
+}
+
+// resolveRace is resolving *TRaceP and *TRaceC at the same time, it fails the test if they deadlock
+func resolveRace(t *testing.T, c *Container) (p *TRaceP, pe error, x *TRaceC, ce error) {
+	ctx := context.Background()
+	BindIn[Provider](c, NewTBarrierProvider(2))
+	BindIn[*TRaceP](c, &TRaceP{})
+	BindIn[*TRaceC](c, &TRaceC{})
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { defer wg.Done(); p, pe = Resolve[*TRaceP](ctx, c) }()
+	go func() { defer wg.Done(); x, ce = Resolve[*TRaceC](ctx, c) }()
+
+	done := make(chan struct{})
+	go func() { wg.Wait(); close(done) }()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("concurrent resolution deadlock")
+	}
+	return
+}
+
+func TestCycledConcurrent(t *testing.T) {
+	// the cycle through the Singleton resolved by two goroutines is error rather than deadlock
+	_, pe, _, ce := resolveRace(t, NewContainer())
+	cycle := (*CycleError)(nil)
+	if !errors.As(pe, &cycle) || !errors.As(ce, &cycle) {
+		t.Fatalf("concurrent cycle error unexpected: %v, %v", pe, ce)
+	}
+	t.Log(pe)
+	t.Log(ce)
+
+	// the Singleton resolved concurrently is resolved once
+	c := NewContainer()
+	BindIn[*TRepo](c, &TRepo{})
+	var wg sync.WaitGroup
+	repos := make([]*TRepo, 8)
+	for i := range repos {
+		wg.Add(1)
+		go func(i int) { defer wg.Done(); repos[i] = MustResolve[*TRepo](context.Background(), c) }(i)
+	}
+	wg.Wait()
+	for _, r := range repos {
+		if r != repos[0] {
+			t.Fatal("concurrent singleton is not shared")
+		}
+	}
+}
+
+type TNestA struct {
+	X string `dix:"from:nesta"`
+}
+
+type TNestB struct {
+	Z string  `dix:"from:nestb"`
+	A *TNestA `dix:"from:?"`
+}
+
+// TNestAProvider is resolving *TNestB in Provide, so the waiting is done by the nested resolution
+type TNestAProvider struct {
+	c       *Container
+	entered chan struct{}
+}
+
+func (TNestAProvider) Symbol() string {
+	return "nesta"
+}
+
+func (p TNestAProvider) Provide(ctx context.Context, tag *Tag) (any, error) {
+	close(p.entered)
+	_, err := Resolve[*TNestB](ctx, p.c)
+	return "a", err
+}
+
+// TNestBProvider is holding the flight of *TNestB until the Provide of *TNestA is waiting it
+type TNestBProvider struct {
+	entered, nested chan struct{}
+}
+
+func (TNestBProvider) Symbol() string {
+	return "nestb"
+}
+
+func (p TNestBProvider) Provide(ctx context.Context, tag *Tag) (any, error) {
+	close(p.entered)
+	<-p.nested
+	time.Sleep(20 * time.Millisecond)
+	return "b", nil
+}
+
+// resolveNested is resolving *TNestB, then *TNestA whose Provider waits *TNestB by the nested DI, at the same time;
+// It fails the test if they deadlock
+func resolveNested(t *testing.T, c *Container) (a *TNestA, ae error, b *TNestB, be error) {
+	ctx := context.Background()
+	x, y := make(chan struct{}), make(chan struct{})
+	BindIn[Provider](c, TNestAProvider{c: c, entered: x})
+	BindIn[Provider](c, TNestBProvider{entered: y, nested: x})
+	BindIn[*TNestA](c, &TNestA{})
+	BindIn[*TNestB](c, &TNestB{})
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { defer wg.Done(); b, be = Resolve[*TNestB](ctx, c) }()
+	go func() { defer wg.Done(); <-y; a, ae = Resolve[*TNestA](ctx, c) }()
+
+	done := make(chan struct{})
+	go func() { wg.Wait(); close(done) }()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("concurrent nested resolution deadlock")
+	}
+	return
+}
+
+func TestCycledConcurrentNested(t *testing.T) {
+	// the cycle through the nested DI of a Provider resolved by two goroutines is error rather than deadlock
+	_, ae, _, be := resolveNested(t, NewContainer())
+	cycle := (*CycleError)(nil)
+	if !errors.As(ae, &cycle) || !errors.As(be, &cycle) {
+		t.Fatalf("concurrent nested cycle error unexpected: %v, %v", ae, be)
+	}
+	t.Log(ae)
+	t.Log(be)
+
+	// the lazy pointer is borrowed rather than deadlock
+	a, ae, b, be := resolveNested(t, NewContainer().SetLazyWiring(true))
+	if ae != nil || be != nil {
+		t.Fatalf("concurrent nested lazy wiring error: %v, %v", ae, be)
+	}
+	if b.A != a || a.X != "a" || b.Z != "b" {
+		t.Fatalf("concurrent nested lazy wiring unexpected: %+v, %+v", a, b)
+	}
+}
+
+type TLazyParent struct {
+	Child *TLazyChild `dix:"from:?"`
+}
+
+type TLazyChild struct {
+	Parent *TLazyParent `dix:"from:?"`
+}
+
+func TestLazyWiring(t *testing.T) {
+	ctx := context.Background()
+
+	c := NewContainer()
+	BindIn[*TLazyParent](c, &TLazyParent{})
+	BindIn[*TLazyChild](c, &TLazyChild{})
+
+	// the pointer cycle is rejected by default
+	ce := (*CycleError)(nil)
+	if _, err := Resolve[*TLazyParent](ctx, c); !errors.As(err, &ce) {
+		t.Fatalf("cycle error unexpected: %v", err)
+	}
+
+	c = NewContainer().SetLazyWiring(true)
+	BindIn[*TLazyParent](c, &TLazyParent{})
+	BindIn[*TLazyChild](c, &TLazyChild{})
+
+	p, err := Resolve[*TLazyParent](ctx, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Child == nil || p.Child.Parent != p {
+		t.Fatalf("lazy wiring unexpected: %+v", p)
+	}
+	if x := MustResolve[*TLazyChild](ctx, c); x != p.Child {
+		t.Fatalf("lazy wiring singleton unexpected: %+v", x)
+	}
+
+	// the value type cycle is still error
+	if _, err = Resolve[TCycleA](ctx, c); !errors.As(err, &ce) {
+		t.Fatalf("value cycle error unexpected: %v", err)
+	}
+}
+
+func TestLazyWiringConcurrent(t *testing.T) {
+	// the pointer cycle resolved by two goroutines is wired rather than deadlock, run with -race
+	for i := 0; i < 20; i++ {
+		p, pe, x, ce := resolveRace(t, NewContainer().SetLazyWiring(true))
+		if pe != nil || ce != nil {
+			t.Fatalf("concurrent lazy wiring error: %v, %v", pe, ce)
+		}
+		if p.C != x || x.P != p || p.Barrier == "" || x.Barrier == "" {
+			t.Fatalf("concurrent lazy wiring unexpected: %+v, %+v", p, x)
+		}
+	}
+
+	// the parallel resolutions of the lazy cycle share the instances
+	c := NewContainer().SetLazyWiring(true)
+	BindIn[*TLazyParent](c, &TLazyParent{})
+	BindIn[*TLazyChild](c, &TLazyChild{})
+	t.Run("parallel", func(t *testing.T) {
+		for i := 0; i < 8; i++ {
+			t.Run("resolve", func(t *testing.T) {
+				t.Parallel()
+				ctx := context.Background()
+				p, err := Resolve[*TLazyParent](ctx, c)
+				if err != nil {
+					t.Fatal(err)
+				}
+				x, err := Resolve[*TLazyChild](ctx, c)
+				if err != nil || p.Child != x || x.Parent != p {
+					t.Fatalf("parallel lazy wiring unexpected: %+v, %+v, %v", p, x, err)
+				}
+			})
+		}
+	})
+}
+
+func TestResolver(t *testing.T) {
+	types := []reflect.Type{reflect.TypeOf(TCycleA{}), reflect.TypeOf(TCycleB{}), reflect.TypeOf(TCycleX{}), reflect.TypeOf(TCycleXY{})}
+
+	rs := newResolver(context.Background())
+	defer rs.release()
+	for _, typ := range types {
+		rs.push(frame{t: typ, field: "F"})
+	}
+
+	// the cycle checks are allocation-free
+	str := reflect.TypeOf("")
+	if n := testing.AllocsPerRun(100, func() { _ = rs.cycled(str) }); n != 0 {
+		t.Fatalf("cycle check allocs %v", n)
+	}
+
+	// the nested DI of Provider continue the resolution path
+	rs.push(frame{symbol: "x", namespace: DefNamespace})
+	nested := newResolver(rs.provide(context.Background()))
+	defer nested.release()
+	rs.pop()
+
+	nested.push(frame{t: str, field: "F"})
+	ce := (*CycleError)(nil)
+	want := []string{"dix.TCycleX.F", "dix.TCycleXY.F", "provider(x, def)", "string.F", "*dix.TCycleX"}
+	if err := nested.cycled(reflect.TypeOf(&TCycleX{})); !errors.As(err, &ce) || !reflect.DeepEqual(ce.Path, want) {
+		t.Fatalf("nested cycle error unexpected: %v", err)
+	}
+	if err := rs.cycled(str); err != nil {
+		t.Fatalf("outer path unexpected: %v", err)
+	}
+}
+
+type TPathOne struct {
+	A int `dix:"from:?"`
+}
+
+type TPathMany struct {
+	A, B, C, D, E, F, G, H int `dix:"from:?"`
+}
+
+func NewTPathMany(a, b, c, d, e, f, g, h int) TPathMany {
+	return TPathMany{a, b, c, d, e, f, g, h}
+}
+
+func TestResolverAllocs(t *testing.T) {
+	if race {
+		t.Skip("the race detector allocates")
+	}
+	ctx := context.Background()
+	c := NewContainer()
+
+	// the resolution path is allocation-free per field and per constructor parameter
+	allocs := func(f func()) float64 { f(); return testing.AllocsPerRun(100, f) }
+	one := allocs(func() { MustResolve[TPathOne](ctx, c) })
+	many := allocs(func() { MustResolve[TPathMany](ctx, c) })
+	if one != many {
+		t.Fatalf("field allocs unexpected: %v, %v", one, many)
+	}
+
+	// the constructor parameters are resolved on the same path
+	if err := c.Constructor(func(a int) TPathOne { return TPathOne{a} }, WithLifetime(Transient)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Constructor(NewTPathMany, WithLifetime(Transient)); err != nil {
+		t.Fatal(err)
+	}
+	one = allocs(func() { MustResolve[TPathOne](ctx, c) })
+	many = allocs(func() { MustResolve[TPathMany](ctx, c) })
+	if one != many {
+		t.Fatalf("param allocs unexpected: %v, %v", one, many)
+	}
+}
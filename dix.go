@@ -2,90 +2,100 @@ package dix
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"os"
 	"reflect"
-	"strings"
+	"sync"
+	"time"
 )
 
-var provider = make(map[string]map[string]Provider, 64)
-var binding = make(map[reflect.Type]map[string]ref, 64)
-var cacheTF = make(map[reflect.Type][]reflect.StructField, 1000)
-var logging = false
-
-const ctxKeyCycled = "dix::ref::cycled"
-
 // ref is the binding information struct
 type ref struct {
-	t reflect.Type
-	v reflect.Value
-}
+	t    reflect.Type
+	v    reflect.Value
+	life Lifetime
+	inst instance // inst is the Singleton instance
 
-// Provider is dependency provider
-type Provider interface {
-	Symbol() string
-	Provide(context.Context, *Tag) (any, error)
+	fn     reflect.Value // fn is the constructor function, v is zero value if set
+	params []string      // params is the constructor parameters dix tag
 }
 
-// Logging is control print log
-func Logging(ok bool) { logging = ok }
+// instance is the resolved value of Singleton or Scoped binding
+type instance struct {
+	mu     sync.Mutex    // mu is guarded the fields, it is never held across the resolution
+	ok     bool          // ok is v resolved and fields injected
+	v      reflect.Value // v is the resolved value
+	flight *flight       // flight is the resolution in progress, nil if none
+}
 
-// Binding is binding Provider and other type, create inject type mapping with namespaces;
-// If you want to set custom values and also want to automatically inject zero values, please use exportable fields
-func Binding[X any](x X, namespaces ...string) {
-	if len(namespaces) == 0 {
-		namespaces = append(namespaces, DefNamespace)
+// land is finishing the flight of the instance, the instance is resolved if no error, otherwise the later DI retry
+func (inst *instance) land(f *flight, v reflect.Value, e error) {
+	inst.mu.Lock()
+	if e == nil {
+		inst.v, inst.ok = v, true
 	}
+	inst.flight = nil
+	inst.mu.Unlock()
 
-	var i interface{} = x
-	switch ix := i.(type) {
-	case Provider:
-		n, ok := provider[ix.Symbol()]
-		if !ok {
-			n = make(map[string]Provider, 8)
-		}
-
-		for _, namespace := range namespaces {
-			n[namespace] = ix
-		}
+	f.v, f.e = v, e
+	close(f.done)
+}
 
-		provider[ix.Symbol()] = n
-		printProvider(ix, namespaces...)
+// build is make the binding instance, call the constructor or use the binding value (copied if need)
+func (c *Container) build(ctx context.Context, rs *resolver, r *ref, copied bool) (reflect.Value, error) {
+	switch {
+	case r.fn.IsValid():
+		return c.construct(ctx, rs, r)
+	case copied:
+		return r.copy(), nil
 	default:
-		e := reflect.TypeOf(&x).Elem()
-		t := reflect.TypeOf(x)
-		v := reflect.ValueOf(x)
-
-		// if struct must convert to addressable reflect.Value
-		if t.Kind() == reflect.Struct {
-			if e.Kind() == reflect.Interface {
-				n := reflect.New(t).Elem()
-				c := n.NumField()
-				for i := 0; i < c; i++ {
-					if f := n.Field(i); f.CanSet() {
-						f.Set(v.Field(i))
-					}
-				}
-				v = n
-			} else {
-				v = reflect.ValueOf(&x).Elem()
-			}
-		}
+		return r.v, nil
+	}
+}
 
-		// binding write with namespaces
-		n, ok := binding[e]
-		if !ok {
-			n = make(map[string]ref, 8)
-		}
-		for _, namespace := range namespaces {
-			n[namespace] = ref{t: t, v: v}
+// copy return a fresh copy of the binding value, struct and pointer kind get a new instance
+func (r *ref) copy() reflect.Value {
+	switch r.v.Kind() {
+	case reflect.Struct:
+		n := reflect.New(r.t).Elem()
+		n.Set(r.v)
+		return n
+	case reflect.Pointer:
+		n := reflect.New(r.t.Elem())
+		if !r.v.IsNil() {
+			n.Elem().Set(r.v.Elem())
 		}
+		return n
+	default:
+		return r.v
+	}
+}
+
+// Provider is dependency provider, the Tag of Provide is a copy for the call, it is reused after Provide returns
+type Provider interface {
+	Symbol() string
+	Provide(context.Context, *Tag) (any, error)
+}
 
-		binding[e] = n
-		printBinding(e, v, namespaces...)
+// Logging is control print log of the default Container with a debug level text logger to stderr;
+//
+// Deprecated: use Default().SetLogger with a *slog.Logger
+func Logging(ok bool) {
+	if ok {
+		defContainer.SetLogger(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	} else {
+		defContainer.SetLogger(nil)
 	}
 }
 
+// Binding is binding Provider and other type, create inject type mapping with namespaces;
+// If you want to set custom values and also want to automatically inject zero values, please use exportable fields
+func Binding[X any](x X, namespaces ...string) {
+	BindIn[X](defContainer, x, namespaces...)
+}
+
 // MustDI is DI wrapped, if happen error will panic
 func MustDI[X any](ctx context.Context) X {
 	x, e := DI[X](ctx)
@@ -98,30 +108,76 @@ func MustDI[X any](ctx context.Context) X {
 // DI is dependency injection method,
 // Cannot dependency on oneself, otherwise circular dependency will occur
 func DI[X any](ctx context.Context) (x X, e error) {
-	tag := NewTag().SetSymbol(TagInvoke)
-	defer tag.Free()
+	return Resolve[X](ctx, defContainer)
+}
 
-	t := reflect.TypeOf(x)
-	v, e := di(ctx, t, tag)
-	if e == nil {
-		x = v.Interface().(X)
+// Inject is populating the caller-owned struct in place with the default Container, see Container.Inject
+func Inject(ctx context.Context, x any) error {
+	return defContainer.Inject(ctx, x)
+}
+
+// di is dependency injection method, rs is the resolution state
+func (c *Container) di(ctx context.Context, rs *resolver, t reflect.Type, tag *Tag) (v reflect.Value, e error) {
+	if tr := c.tracer(); tr != nil {
+		start := time.Now()
+		ctx = tr.OnResolveStart(ctx, t, tag)
+		defer func() { tr.OnResolveEnd(ctx, t, time.Since(start), e) }()
 	}
 
-	return x, e
-}
+	ex := rs.ex
 
-// di is dependency injection method
-func di(ctx context.Context, t reflect.Type, tag *Tag) (v reflect.Value, e error) {
 	// try provide
-	if v, e = provide(ctx, tag, t); e != nil || v.IsValid() {
+	if v, e = c.provide(ctx, rs, tag, t); e != nil || v.IsValid() {
+		if ex != nil && v.IsValid() {
+			ex.Source, ex.Symbol, ex.Namespace = SourceProvider, tag.GetSymbol(), namespace(ctx, tag)
+		}
+		return
+	}
+
+	// try bound, the Singleton is resolved by the Container owns it, otherwise by the Container of DI
+	if r, owner := c.bound(ctx, tag, t); r != nil {
+		if ex != nil {
+			ex.Source, ex.Namespace, ex.Lifetime = SourceBinding, namespace(ctx, tag), r.life.String()
+			if r.fn.IsValid() {
+				ex.Source = SourceConstructor
+			}
+		}
+
+		var cached bool
+		switch r.life {
+		case Transient:
+			if v, e = c.build(ctx, rs, r, true); e != nil {
+				return
+			}
+			return v, c.create(ctx, rs, v, rs.own)
+		case Scoped:
+			inst, _ := c.scoped.LoadOrStore(r, &instance{})
+			v, cached, e = c.once(ctx, rs, r, inst.(*instance), true)
+		default:
+			v, cached, e = owner.once(ctx, rs, r, &r.inst, false)
+		}
+		if cached {
+			c.cacheHit(ctx, t)
+		}
+		if ex != nil {
+			ex.Cached = cached
+		}
 		return
 	}
 
 	// try invoke
-	if v, e = invoke(ctx, tag, t); e != nil || !v.IsValid() {
+	if v, e = c.invoke(ctx, tag, t); e != nil || !v.IsValid() {
 		return
 	}
+	if ex != nil {
+		ex.Source = SourceInvoke
+	}
+
+	return v, c.create(ctx, rs, v, rs.own)
+}
 
+// fill is injecting the fields of struct kind value, pointer kind is dereference layer by layer
+func (c *Container) fill(ctx context.Context, rs *resolver, v reflect.Value) error {
 	// pointer kind need to invoke and set layer by layer
 	//p := v
 	//for p.Kind() == reflect.Pointer {
@@ -145,12 +201,99 @@ func di(ctx context.Context, t reflect.Type, tag *Tag) (v reflect.Value, e error
 		x = x.Elem()
 	}
 
-	// struct kind need to inject the value of the field
+	// struct kind need to inject the value of the field, then call the Initializer
 	if x.Kind() == reflect.Struct {
-		return v, inject(ctx, tag, x)
+		if e := c.inject(ctx, rs, x); e != nil {
+			return e
+		}
+		if x.CanAddr() {
+			x = x.Addr()
+		}
+		return initialize(ctx, x)
 	}
 
-	return v, e
+	return nil
+}
+
+// create is filling the instance made by the Container, the Container owns it if owned
+func (c *Container) create(ctx context.Context, rs *resolver, v reflect.Value, owned bool) error {
+	if e := c.fill(ctx, rs, v); e != nil {
+		return e
+	}
+	if owned {
+		c.own(v)
+	}
+	return nil
+}
+
+// once is resolving the binding instance exactly once, the instance is shared by all DI of the same owner;
+// The resolution is single-flight, the concurrent DI wait for the flight in progress rather than a lock held across
+// the resolution, so the waiting is checked for deadlock like the cycles; if copied the instance is a copy of the
+// binding value, cached is the instance resolved before or by other DI
+func (c *Container) once(ctx context.Context, rs *resolver, r *ref, inst *instance, copied bool) (v reflect.Value, cached bool, e error) {
+	// the lazy Singleton pointer is allocated before wiring, the recurrence on the same path get the pointer
+	lazy := !copied && !r.fn.IsValid() && r.t.Kind() == reflect.Pointer && c.lazy.Load()
+	if lazy && rs.wiring(inst) {
+		return r.v, true, nil
+	}
+
+	// check is cycled dependency on the path, the recurrence would wait for itself
+	if e = rs.cycled(r.t); e != nil {
+		return
+	}
+
+	inst.mu.Lock()
+	if inst.ok {
+		v = inst.v
+		inst.mu.Unlock()
+		return v, true, nil
+	}
+	if f := inst.flight; f != nil {
+		inst.mu.Unlock()
+		// the lazy pointer in progress by other resolver waiting for this one is used before wired, see settle
+		borrowed := false
+		if v, borrowed, e = rs.await(f, r.t, lazy); borrowed {
+			return r.v, true, nil
+		}
+		return v, e == nil, e
+	}
+	f := &flight{owner: rs.waiter(), done: make(chan struct{})}
+	inst.flight = f
+	inst.mu.Unlock()
+
+	// the flight is always landed, the waiters of a panicked resolution get error
+	landed := false
+	defer func() {
+		if !landed {
+			inst.land(f, reflect.Value{}, fmt.Errorf("dix: `%s` resolution panicked", r.t))
+		}
+	}()
+
+	if lazy {
+		rs.push(frame{t: r.t, inst: inst})
+	}
+	if v, e = c.build(ctx, rs, r, copied); e == nil {
+		e = c.create(ctx, rs, v, true)
+	}
+	if lazy {
+		rs.pop()
+	}
+	if e != nil {
+		v = reflect.Value{}
+	}
+
+	inst.land(f, v, e)
+	landed = true
+
+	return v, false, e
+}
+
+// deref is the real type of pointer type
+func deref(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
 }
 
 // namespace get the namespace tag, if notfound use the default namespace
@@ -162,34 +305,73 @@ func namespace(ctx context.Context, tag *Tag) (namespace string) {
 }
 
 // provide is take a Provider with Provider’Symbol and call Provider’Provide method
-func provide(ctx context.Context, tag *Tag, t reflect.Type) (v reflect.Value, e error) {
+func (c *Container) provide(ctx context.Context, rs *resolver, tag *Tag, t reflect.Type) (v reflect.Value, e error) {
 	if symbol := tag.GetSymbol(); len(symbol) > 0 {
-		if namespaces, ok := provider[symbol]; ok {
-			if provider, ok := namespaces[namespace(ctx, tag)]; ok && provider != nil {
-				switch x, e := provider.Provide(ctx, tag); {
-				case e != nil:
-					return v, e
-				case x:
-					return reflect.Zero(t), nil
-				default:
-					return reflect.ValueOf(x), nil
-				}
+		ns := namespace(ctx, tag)
+		provider := c.lookupProvider(symbol, ns)
+		if provider == nil {
+			// the symbol not bound is an error, the field is not left zero silently, see README Article 1
+			if symbol != TagInvoke {
+				return v, &ProviderError{Symbol: symbol, Namespace: ns, Err: ErrNotBound}
 			}
+			return v, nil
+		}
+
+		switch x, e := c.callProvider(ctx, rs, provider, tag, symbol, ns); {
+		case e != nil:
+			return v, e
+		case x:
+			return reflect.Zero(t), nil
+		default:
+			return reflect.ValueOf(x), nil
 		}
 	}
 	return v, e
 }
 
-// invoke is invoked with reflect
-func invoke(ctx context.Context, tag *Tag, t reflect.Type) (v reflect.Value, e error) {
+// callProvider is calling the Provide method of the Provider of symbol in the namespace, the error is ProviderError
+func (c *Container) callProvider(ctx context.Context, rs *resolver, provider Provider, tag *Tag, symbol, ns string) (any, error) {
+	// check is cycled Provider, the Provider may DI with ctx again
+	if e := rs.cycledProvider(symbol, ns); e != nil {
+		return nil, e
+	}
+
+	// the Provider DI with ctx continue the resolution path, but it is not a part of the Explanation
+	rs.push(frame{symbol: symbol, namespace: ns})
+	pctx := rs.provide(ctx)
+	rs.pop()
+
+	// the Provider get a copy of the tag, the tag of the plan is shared
+	ptag := tag.clone()
+	defer ptag.Free()
+
+	tr := c.tracer()
+	var start time.Time
+	if tr != nil {
+		start = time.Now()
+	}
+	x, e := provider.Provide(pctx, ptag)
+	if tr != nil {
+		tr.OnProvide(ctx, symbol, ns, time.Since(start), e)
+	}
+	if e != nil {
+		return nil, &ProviderError{Symbol: symbol, Namespace: ns, Err: e}
+	}
+	return x, nil
+}
+
+// bound is take the binding of the type in the namespace and the Container owns it
+func (c *Container) bound(ctx context.Context, tag *Tag, t reflect.Type) (*ref, *Container) {
 	if tag.GetSymbol() != TagInvoke {
-		return
+		return nil, nil
 	}
+	return c.lookupBinding(t, namespace(ctx, tag))
+}
 
-	if namespaces, ok := binding[t]; ok {
-		if bind, ok := namespaces[namespace(ctx, tag)]; ok {
-			return bind.v, nil
-		}
+// invoke is invoked with reflect
+func (c *Container) invoke(ctx context.Context, tag *Tag, t reflect.Type) (v reflect.Value, e error) {
+	if tag.GetSymbol() != TagInvoke {
+		return
 	}
 
 	switch t.Kind() {
@@ -223,86 +405,117 @@ func invoke(ctx context.Context, tag *Tag, t reflect.Type) (v reflect.Value, e e
 	}
 }
 
-// inject is injecting instantiated values into fields
-func inject(ctx context.Context, tag *Tag, v reflect.Value) (e error) {
+// inject is injecting instantiated values into fields by the compiled plan of type,
+// the error is joined FieldError of all failing fields
+func (c *Container) inject(ctx context.Context, rs *resolver, v reflect.Value) (e error) {
 	t := v.Type()
 
 	// check is cycled dependency
-	if ctx, e = cycled(ctx, t); e != nil {
+	if e = rs.cycled(t); e != nil {
 		return
 	}
 
-	// build type fields cache
-	sfs, ok := cacheTF[t]
-	if !ok {
-		nf := v.NumField()
-		sfs = make([]reflect.StructField, nf)
-		for i := 0; i < nf; i++ {
-			sfs[i] = t.Field(i) // many alloc, so need cache
-		}
-		cacheTF[t] = sfs
-	}
+	// inject by the plan, continue across all fields and collect the errors
+	var errs []error
+	ex := rs.ex
+	defer func() { rs.ex = ex }()
+
+	// the time of the field resolution is only taken for the debug log
+	debug := c.debug(ctx)
 
-	// inject from fields cache
-	for i, sf := range sfs {
-		val, ok := sf.Tag.Lookup(TagDix)
-		if !ok {
-			continue
+	p := planOf(t)
+	for i := range p.fields {
+		f := &p.fields[i]
+
+		var fex *Explanation
+		if ex != nil {
+			fex = ex.field(&f.sf, f.val)
 		}
 
-		if vf := v.Field(i); vf.CanSet() && vf.IsZero() {
-			switch x, e := di(ctx, vf.Type(), tag.Reset().Unmarshal(val)); {
+		vf := v.Field(f.index)
+		switch {
+		case !f.set:
+			if fex != nil {
+				fex.Source = SourceUnexported
+			}
+		case f.err != nil:
+			if fex != nil {
+				fex.resolved("", f.err)
+			}
+			errs = appendFieldError(errs, t, &f.sf, f.val, f.err)
+		case !vf.IsZero():
+			if fex != nil {
+				fex.Source = SourcePreset
+				fex.resolved(c.format(vf, &f.sf, f.tag.GetSecret()), nil)
+			}
+		case f.resolve != nil:
+			var start time.Time
+			if debug {
+				start = time.Now()
+			}
+			rs.push(frame{t: t, field: f.sf.Name})
+			rs.ex = fex
+			x, e := f.resolve(c, ctx, rs)
+			rs.pop()
+			if fex != nil {
+				fex.resolved(c.format(x, &f.sf, f.tag.GetSecret()), e)
+			}
+
+			switch {
 			case e != nil:
-				return fmt.Errorf("`%s` field `%s %s` di error: %w \n", t, sf.Name, sf.Type, e)
+				errs = appendFieldError(errs, t, &f.sf, f.val, e)
 			case x.IsValid():
-				printInject(t, x, &sf)
+				if debug {
+					c.logInject(ctx, t, f, x, time.Since(start))
+				}
 				vf.Set(x)
 			}
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
-// cycled is checked the cycled dependency
-func cycled(ctx context.Context, t reflect.Type) (context.Context, error) {
-	if len(t.PkgPath()) > 0 {
-		np := strings.Join([]string{t.PkgPath(), t.Name()}, ".")
-		cv := ctx.Value(ctxKeyCycled)
-		if cv == nil {
-			ctx = context.WithValue(ctx, ctxKeyCycled, np)
-			return ctx, nil
-		}
-
-		pp := cv.(string)
-		if strings.Contains(pp, np) {
-			return ctx, fmt.Errorf("cycled dependency(%s)", np)
-		}
-
-		np = strings.Join([]string{pp, np}, ",")
-		ctx = context.WithValue(ctx, ctxKeyCycled, np)
+// logProvider will be log Provider binding information
+func (c *Container) logProvider(x Provider, namespaces ...string) {
+	if l := c.logger(); l != nil && l.Enabled(context.Background(), slog.LevelDebug) {
+		l.LogAttrs(context.Background(), slog.LevelDebug, "dix provider binding",
+			slog.String("symbol", x.Symbol()),
+			slog.String("provider", fmt.Sprintf("%T", x)),
+			slog.Any("namespaces", namespaces),
+		)
 	}
-
-	return ctx, nil
 }
 
-// printProvider will be print Provider information
-func printProvider(x Provider, namespaces ...string) {
-	if logging {
-		log.Printf("[dix] `dix.Provider` binding `%#v` using %s\n", x, namespaces)
+// logBinding will be log type binding information
+func (c *Container) logBinding(t reflect.Type, v reflect.Value, l Lifetime, secret bool, namespaces ...string) {
+	if lg := c.logger(); lg != nil && lg.Enabled(context.Background(), slog.LevelDebug) {
+		lg.LogAttrs(context.Background(), slog.LevelDebug, "dix type binding",
+			slog.String("type", t.String()),
+			slog.String("value", c.format(v, nil, secret)),
+			slog.String("lifetime", l.String()),
+			slog.Any("namespaces", namespaces),
+		)
 	}
 }
 
-// printBinding will be print Binding information
-func printBinding(t reflect.Type, v reflect.Value, namespaces ...string) {
-	if logging {
-		log.Printf("[dix] `%s` binding `%#v` using %s\n", t, v, namespaces)
-	}
+// debug is checked the debug log is enabled
+func (c *Container) debug(ctx context.Context) bool {
+	l := c.logger()
+	return l != nil && l.Enabled(ctx, slog.LevelDebug)
 }
 
-// printInject will be print struct fields inject information
-func printInject(t reflect.Type, v reflect.Value, sf *reflect.StructField) {
-	if logging {
-		log.Printf("[dix] `%s` field `%s %s` di -> %#v\n", t, sf.Name, sf.Type, v)
+// logInject will be log struct field inject information, the debug log is enabled
+func (c *Container) logInject(ctx context.Context, t reflect.Type, f *field, v reflect.Value, d time.Duration) {
+	if l := c.logger(); l != nil {
+		l.LogAttrs(ctx, slog.LevelDebug, "dix field inject",
+			slog.String("type", t.String()),
+			slog.String("field", f.sf.Name),
+			slog.String("field_type", f.sf.Type.String()),
+			slog.String("symbol", f.tag.GetSymbol()),
+			slog.String("namespace", namespace(ctx, f.tag)),
+			slog.String("value", c.format(v, &f.sf, f.tag.GetSecret())),
+			slog.Duration("duration", d),
+		)
 	}
 }
@@ -225,3 +225,30 @@ func BenchmarkDI(b *testing.B) {
 		}
 	}
 }
+
+type TTagProvider struct{}
+
+func (TTagProvider) Symbol() string {
+	return "TTP"
+}
+
+// Provide is modifying the tag, the tag of the next DI is not changed
+func (TTagProvider) Provide(ctx context.Context, tag *Tag) (any, error) {
+	v, _ := tag.GetCustomize("v")
+	tag.Reset().SetCustomize("v", "modified")
+	return v, nil
+}
+
+func TestProviderTag(t *testing.T) {
+	c := NewContainer()
+	BindIn[Provider](c, TTagProvider{})
+
+	type X struct {
+		V string `dix:"from:TTP;v:origin"`
+	}
+	for i := 0; i < 2; i++ {
+		if x := MustResolve[X](context.Background(), c); x.V != "origin" {
+			t.Fatalf("provider tag unexpected: %q", x.V)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package dix
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrNotBound is the sentinel error of the Provider symbol or type not bound in the namespace
+var ErrNotBound = errors.New("dix: not bound")
+
+// CycleError is the cycled dependency error, Path is the dependency path ending with the recurrence
+type CycleError struct {
+	Path []string
+}
+
+func (e *CycleError) Error() string {
+	return "dix: cycled dependency " + strings.Join(e.Path, " -> ")
+}
+
+// ProviderError is the Provider error, Symbol and Namespace is the Provider binding
+type ProviderError struct {
+	Symbol    string
+	Namespace string
+	Err       error
+}
+
+func (e *ProviderError) Error() string {
+	return fmt.Sprintf("dix: provider `%s` in namespace `%s`: %v", e.Symbol, e.Namespace, e.Err)
+}
+
+func (e *ProviderError) Unwrap() error { return e.Err }
+
+// TagError is the dix tag syntax or value error, Offset is the byte offset of the problem in Tag
+type TagError struct {
+	Tag    string
+	Offset int
+	Msg    string
+}
+
+func (e *TagError) Error() string {
+	return fmt.Sprintf("dix: tag `%s` at offset %d: %s", e.Tag, e.Offset, e.Msg)
+}
+
+// FieldError is the struct field injection error,
+// Type is the root struct type, Path is the field path from the root struct, Tag is the dix tag of the last field
+type FieldError struct {
+	Type reflect.Type
+	Path []string
+	Tag  string
+	Err  error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("dix: `%s` field `%s` di error: %v", e.Type, strings.Join(e.Path, "."), e.Err)
+}
+
+func (e *FieldError) Unwrap() error { return e.Err }
+
+// appendFieldError is appending the error of struct field, the joined errors are flattened
+func appendFieldError(errs []error, t reflect.Type, sf *reflect.StructField, tag string, err error) []error {
+	if je, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, err := range je.Unwrap() {
+			errs = appendFieldError(errs, t, sf, tag, err)
+		}
+		return errs
+	}
+	return append(errs, fieldError(t, sf, tag, err))
+}
+
+// fieldError is wrapped the error of struct field, the nested FieldError is merged into the field path
+func fieldError(t reflect.Type, sf *reflect.StructField, tag string, err error) *FieldError {
+	if fe, ok := err.(*FieldError); ok {
+		path := make([]string, 0, len(fe.Path)+1)
+		path = append(append(path, sf.Name), fe.Path...)
+		return &FieldError{Type: t, Path: path, Tag: fe.Tag, Err: fe.Err}
+	}
+	return &FieldError{Type: t, Path: []string{sf.Name}, Tag: tag, Err: err}
+}
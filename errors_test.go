@@ -0,0 +1,102 @@
+package dix
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type TCycleA struct {
+	B *TCycleB `dix:"from:?"`
+}
+
+type TCycleB struct {
+	A *TCycleA `dix:"from:?"`
+}
+
+func TestErrors(t *testing.T) {
+	ctx := context.Background()
+	c := NewContainer()
+
+	_, err := Resolve[TCycleA](ctx, c)
+	if ce := (*CycleError)(nil); !errors.As(err, &ce) || len(ce.Path) != 3 {
+		t.Fatalf("cycle error unexpected: %v", err)
+	}
+
+	type Y struct {
+		TB TB `dix:"from:TBP;namespace:ns1"`
+	}
+	type X struct {
+		Y Y `dix:"from:?"`
+	}
+
+	_, err = Resolve[X](ctx, c)
+	fe, pe := (*FieldError)(nil), (*ProviderError)(nil)
+	if !errors.As(err, &fe) || fe.Type.Name() != "X" || len(fe.Path) != 2 || fe.Path[1] != "TB" {
+		t.Fatalf("field error unexpected: %v", err)
+	}
+	if !errors.As(err, &pe) || pe.Symbol != "TBP" || pe.Namespace != "ns1" || !errors.Is(err, ErrNotBound) {
+		t.Fatalf("provider error unexpected: %v", err)
+	}
+	t.Log(err)
+}
+
+func TestTagErrors(t *testing.T) {
+	ctx := context.Background()
+	c := NewContainer()
+
+	type X struct {
+		Ok   []int  `dix:"from:?;slice_len:1;slice_cap:1"`
+		Bad  []int  `dix:"from:?;slice_len:x"`
+		Name string `dix:"from:'name"`
+	}
+
+	// the invalid tags are the field errors, the valid fields are still injected
+	x := X{}
+	err := c.Inject(ctx, &x)
+	je, ok := err.(interface{ Unwrap() []error })
+	if !ok || len(je.Unwrap()) != 2 || len(x.Ok) != 1 {
+		t.Fatalf("tag errors unexpected: %v", err)
+	}
+	fe, te := (*FieldError)(nil), (*TagError)(nil)
+	if !errors.As(je.Unwrap()[1], &fe) || fe.Path[0] != "Name" || !errors.As(fe, &te) || te.Offset != 5 {
+		t.Fatalf("tag error unexpected: %v", je.Unwrap()[1])
+	}
+
+	// the constructor parameter tags are checked at binding
+	if err = c.Constructor(func(string) *TRepo { return nil }, WithParamTags("from:'cfg")); !errors.As(err, &te) {
+		t.Fatalf("param tag error unexpected: %v", err)
+	}
+	t.Log(err)
+}
+
+func TestFieldErrors(t *testing.T) {
+	type Y struct {
+		A  int `dix:"from:?"`
+		P1 int `dix:"from:p1"`
+		P2 int `dix:"from:p2;namespace:ns2"`
+	}
+	type X struct {
+		P0 int `dix:"from:p0"`
+		Y  *Y  `dix:"from:?"`
+		B  int `dix:"from:?"`
+	}
+
+	_, err := Resolve[X](context.Background(), NewContainer())
+	je, ok := err.(interface{ Unwrap() []error })
+	if !ok || len(je.Unwrap()) != 3 {
+		t.Fatalf("field errors unexpected: %v", err)
+	}
+
+	want := [][]string{{"P0"}, {"Y", "P1"}, {"Y", "P2"}}
+	for i, err := range je.Unwrap() {
+		fe := (*FieldError)(nil)
+		if !errors.As(err, &fe) || len(fe.Path) != len(want[i]) || fe.Path[len(fe.Path)-1] != want[i][len(want[i])-1] {
+			t.Fatalf("field error %d unexpected: %v", i, err)
+		}
+		if !errors.Is(err, ErrNotBound) {
+			t.Fatalf("field error %d unexpected: %v", i, err)
+		}
+	}
+	t.Log(err)
+}
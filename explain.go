@@ -0,0 +1,127 @@
+package dix
+
+import (
+	"context"
+	"reflect"
+	"strings"
+)
+
+// Explanation sources
+const (
+	// SourceProvider is the value from the Provider
+	SourceProvider = "provider"
+	// SourceBinding is the value from the type binding
+	SourceBinding = "binding"
+	// SourceConstructor is the value from the constructor binding
+	SourceConstructor = "constructor"
+	// SourceInvoke is the zero or `make` value invoked with reflect
+	SourceInvoke = "invoke"
+	// SourcePreset is the field not zero, it is not injected
+	SourcePreset = "preset"
+	// SourceUnexported is the field unexported, it can not be injected
+	SourceUnexported = "unexported"
+	// SourceUnresolved is the field not resolved, like the dix tag without `from` or invalid
+	SourceUnresolved = "unresolved"
+)
+
+// Explanation is the tree of where each injected value came from
+type Explanation struct {
+	Type      string         `json:"type"`
+	Field     string         `json:"field,omitempty"`
+	Tag       string         `json:"tag,omitempty"`
+	Source    string         `json:"source"`
+	Symbol    string         `json:"symbol,omitempty"`
+	Namespace string         `json:"namespace,omitempty"`
+	Lifetime  string         `json:"lifetime,omitempty"`
+	Cached    bool           `json:"cached,omitempty"`
+	Value     string         `json:"value,omitempty"`
+	Error     string         `json:"error,omitempty"`
+	Fields    []*Explanation `json:"fields,omitempty"`
+}
+
+// Explain is DI with the default Container and explaining where each injected value came from
+func Explain[X any](ctx context.Context) (X, *Explanation, error) {
+	return ExplainIn[X](ctx, defContainer)
+}
+
+// ExplainIn is Resolve with the Container and explaining where each injected value came from,
+// the Explanation is returned even if resolve error
+func ExplainIn[X any](ctx context.Context, c *Container) (x X, ex *Explanation, e error) {
+	tag := NewTag().SetSymbol(TagInvoke)
+	defer tag.Free()
+
+	t := reflect.TypeOf((*X)(nil)).Elem()
+	ex = &Explanation{Type: t.String()}
+
+	rs := newResolver(ctx)
+	defer rs.release()
+
+	rs.ex = ex
+	v, e := c.di(ctx, rs, t, tag)
+	if e == nil {
+		e = rs.settle()
+	}
+	if e == nil {
+		x = assign[X](v)
+	}
+	ex.resolved(c.format(v, nil, false), e)
+
+	return x, ex, e
+}
+
+// field is adding the Explanation of struct field
+func (ex *Explanation) field(sf *reflect.StructField, tag string) *Explanation {
+	f := &Explanation{Type: sf.Type.String(), Field: sf.Name, Tag: tag, Source: SourceUnresolved}
+	ex.Fields = append(ex.Fields, f)
+	return f
+}
+
+// resolved is recording the resolved value (formatted with redaction) or error
+func (ex *Explanation) resolved(value string, e error) {
+	if e != nil {
+		ex.Error = e.Error()
+	} else {
+		ex.Value = value
+	}
+}
+
+// String return the Explanation tree, one line per value
+func (ex *Explanation) String() string {
+	var b strings.Builder
+	ex.write(&b, 0)
+	return b.String()
+}
+
+// write is writing the Explanation tree with depth indent
+func (ex *Explanation) write(b *strings.Builder, depth int) {
+	b.WriteString(strings.Repeat("  ", depth))
+	if ex.Field != "" {
+		b.WriteString(ex.Field + " ")
+	}
+	b.WriteString(ex.Type + " <- " + ex.Source)
+	if ex.Symbol != "" {
+		b.WriteString(" " + ex.Symbol)
+	}
+	if ex.Namespace != "" {
+		b.WriteString(" @" + ex.Namespace)
+	}
+	if ex.Lifetime != "" {
+		b.WriteString(" (" + ex.Lifetime + ")")
+	}
+	if ex.Cached {
+		b.WriteString(" cached")
+	}
+	if ex.Tag != "" {
+		b.WriteString(" `" + TagDix + ":\"" + ex.Tag + "\"`")
+	}
+	if ex.Error != "" {
+		b.WriteString(" error: " + ex.Error)
+	} else if ex.Value != "" {
+		b.WriteString(" = " + ex.Value)
+	}
+	b.WriteString("\n")
+
+	for _, f := range ex.Fields {
+		f.write(b, depth+1)
+	}
+}
@@ -0,0 +1,56 @@
+package dix
+
+import (
+	"context"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	c := NewContainer()
+	BindIn[Provider](c, TBProvider{})
+	BindIn[int](c, 10, "ns1")
+	BindIn[TConfig](c, TConfig{DSN: "dsn"})
+	_ = c.Constructor(func(cfg TConfig) *TRepo { return &TRepo{Config: cfg} })
+
+	type X struct {
+		TB      TB     `dix:"from:TBP"`
+		Int     int    `dix:"from:?;namespace:ns1"`
+		Str     string `dix:"from:?"`
+		Repo    *TRepo `dix:"from:?"`
+		Preset  int    `dix:"from:?"`
+		private int    `dix:"from:?"`
+		NoFrom  int    `dix:"namespace:ns1"`
+	}
+
+	BindIn[X](c, X{Preset: 5})
+
+	_, ex, err := ExplainIn[X](context.Background(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ex.Source != SourceBinding || ex.Lifetime != "singleton" || len(ex.Fields) != 7 {
+		t.Fatalf("explain unexpected:\n%s", ex)
+	}
+
+	want := []struct{ field, source, ns string }{
+		{"TB", SourceProvider, DefNamespace},
+		{"Int", SourceBinding, "ns1"},
+		{"Str", SourceInvoke, ""},
+		{"Repo", SourceConstructor, DefNamespace},
+		{"Preset", SourcePreset, ""},
+		{"private", SourceUnexported, ""},
+		{"NoFrom", SourceUnresolved, ""},
+	}
+	for i, w := range want {
+		if f := ex.Fields[i]; f.Field != w.field || f.Source != w.source || f.Namespace != w.ns {
+			t.Fatalf("explain field %d unexpected: %+v", i, f)
+		}
+	}
+	if ex.Fields[1].Value != "10" || ex.Fields[0].Symbol != "TBP" {
+		t.Fatalf("explain values unexpected:\n%s", ex)
+	}
+	if repo := ex.Fields[3]; len(repo.Fields) != 2 || repo.Fields[0].Field != "param0" || repo.Fields[1].Field != "TB" {
+		t.Fatalf("explain constructor unexpected:\n%s", ex)
+	}
+	t.Log("\n" + ex.String())
+}
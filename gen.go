@@ -0,0 +1,51 @@
+package dix
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// The functions of this file are used by the code generated by cmd/dixgen, the generated injectors wire the unbound
+// types without reflection and use these functions for the Provider, the type binding and the errors
+
+// Bound is checked the type X has binding in the namespace of tag, walk the chain child to parent
+func Bound[X any](c *Container, tag *Tag) bool {
+	r, _ := c.lookupBinding(reflect.TypeOf((*X)(nil)).Elem(), namespace(context.Background(), tag))
+	return r != nil
+}
+
+// Provide is calling the Provider of the tag symbol in the namespace directly, the provided value must be X;
+// The nil value is the zero value of X, the error is ProviderError
+func Provide[X any](ctx context.Context, c *Container, tag *Tag) (x X, e error) {
+	symbol, ns := tag.GetSymbol(), namespace(ctx, tag)
+	provider := c.lookupProvider(symbol, ns)
+	if provider == nil {
+		return x, &ProviderError{Symbol: symbol, Namespace: ns, Err: ErrNotBound}
+	}
+
+	rs := newResolver(ctx)
+	defer rs.release()
+
+	v, e := c.callProvider(ctx, rs, provider, tag, symbol, ns)
+	if e == nil {
+		e = rs.settle()
+	}
+	if e != nil || v == nil {
+		return x, e
+	}
+	if x, ok := v.(X); ok {
+		return x, nil
+	}
+	return x, &ProviderError{Symbol: symbol, Namespace: ns, Err: fmt.Errorf("provided `%T` is not `%T`", v, x)}
+}
+
+// AppendFieldError is appending the error of struct X field like the Container inject, the nested FieldError is merged
+func AppendFieldError[X any](errs []error, field, tag string, err error) []error {
+	return appendFieldError(errs, reflect.TypeOf((*X)(nil)).Elem(), &reflect.StructField{Name: field}, tag, err)
+}
+
+// IsZero is checked x is the zero value, for the types not comparable with `==`
+func IsZero[X any](x X) bool {
+	return reflect.ValueOf(&x).Elem().IsZero()
+}
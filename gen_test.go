@@ -0,0 +1,49 @@
+package dix
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGen(t *testing.T) {
+	ctx := context.Background()
+	c := NewContainer()
+	BindIn[Provider](c, TBProvider{})
+	BindIn[int](c, 10, "ns1")
+
+	tag := NewTag("from:?;namespace:ns1")
+	defer tag.Free()
+	if !Bound[int](c, tag) || Bound[string](c, tag) || !Bound[int](c.Child(), tag) {
+		t.Fatal("bound unexpected")
+	}
+	if x, err := ResolveTag[int](ctx, c, tag); err != nil || x != 10 {
+		t.Fatalf("resolve tag unexpected: %v %v", x, err)
+	}
+
+	ptag := NewTag("from:TBP")
+	defer ptag.Free()
+	if x, err := Provide[TB](ctx, c, ptag); err != nil || x.Int8 != 100 {
+		t.Fatalf("provide unexpected: %+v %v", x, err)
+	}
+	pe := (*ProviderError)(nil)
+	if _, err := Provide[int](ctx, c, ptag); !errors.As(err, &pe) || pe.Symbol != "TBP" {
+		t.Fatalf("provide type error unexpected: %v", err)
+	}
+	if _, err := Provide[TB](ctx, NewContainer(), ptag); !errors.Is(err, ErrNotBound) {
+		t.Fatalf("provide not bound unexpected: %v", err)
+	}
+
+	type X struct {
+		Y struct{ M map[int]int }
+	}
+	if !IsZero(X{}) || IsZero(X{Y: struct{ M map[int]int }{M: map[int]int{}}}) {
+		t.Fatal("is zero unexpected")
+	}
+
+	errs := AppendFieldError[X](nil, "Y", "from:?", &FieldError{Path: []string{"M"}, Tag: "from:?", Err: ErrNotBound})
+	fe := (*FieldError)(nil)
+	if !errors.As(errors.Join(errs...), &fe) || fe.Type.Name() != "X" || len(fe.Path) != 2 {
+		t.Fatalf("field error unexpected: %v", errs)
+	}
+}
@@ -0,0 +1,325 @@
+package dix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Graph node kinds
+const (
+	// NodeType is the type invoked with reflect
+	NodeType = "type"
+	// NodeBinding is the type binding value
+	NodeBinding = "binding"
+	// NodeConstructor is the type binding constructor
+	NodeConstructor = "constructor"
+	// NodeProvider is the Provider
+	NodeProvider = "provider"
+	// NodeMissing is the Provider not bound
+	NodeMissing = "missing"
+	// NodeInvalid is the field with invalid dix tag, it is never resolved, the label is the TagError
+	NodeInvalid = "invalid"
+)
+
+// Graph is the resolution graph of the root types
+type Graph struct {
+	Roots []string    `json:"roots"`
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+
+	index map[string]int
+}
+
+// GraphNode is the type or Provider node of Graph, the bound type and Provider node has namespace;
+// The type node is identified and labeled by the type name with the full package path like `*github.com/org/pkg.T`
+type GraphNode struct {
+	ID        string `json:"id"`
+	Kind      string `json:"kind"`
+	Label     string `json:"label"`
+	Namespace string `json:"namespace,omitempty"`
+	Lifetime  string `json:"lifetime,omitempty"`
+}
+
+// GraphEdge is the dependency edge of Graph, labeled with the field (or constructor parameter) name and dix tag
+type GraphEdge struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Field string `json:"field"`
+	Tag   string `json:"tag,omitempty"`
+}
+
+// GraphOf is rendering the resolution graph of X with the default Container, see Container.Graph
+func GraphOf[X any]() *Graph {
+	return defContainer.Graph(reflect.TypeOf((*X)(nil)).Elem())
+}
+
+// Graph is rendering the resolution graph of the root types without calling Provide or allocating instances
+func (c *Container) Graph(types ...reflect.Type) *Graph {
+	g := &Graph{index: make(map[string]int)}
+	for _, t := range types {
+		tag := NewTag().SetSymbol(TagInvoke)
+		g.Roots = append(g.Roots, c.graph(g, t, tag))
+		tag.Free()
+	}
+	return g
+}
+
+// graph is adding the node of the type resolved with tag and its dependencies, return the node id
+func (c *Container) graph(g *Graph, t reflect.Type, tag *Tag) string {
+	symbol, ns := tag.GetSymbol(), namespace(context.Background(), tag)
+	if symbol != TagInvoke {
+		node := GraphNode{ID: "provider:" + symbol + "@" + ns, Kind: NodeProvider, Label: symbol, Namespace: ns}
+		if c.lookupProvider(symbol, ns) == nil {
+			node.Kind = NodeMissing
+		}
+		g.add(node)
+		return node.ID
+	}
+
+	r, owner := c.lookupBinding(t, ns)
+	if r == nil {
+		node := GraphNode{ID: typeName(t), Kind: NodeType, Label: typeName(t)}
+		if g.add(node) {
+			c.graphFields(g, node.ID, t)
+		}
+		return node.ID
+	}
+
+	// the Singleton is resolved by the Container owns it
+	if r.life == Singleton {
+		c = owner
+	}
+
+	node := GraphNode{ID: typeName(t) + "@" + ns, Kind: NodeBinding, Label: typeName(r.t), Namespace: ns, Lifetime: r.life.String()}
+	if r.fn.IsValid() {
+		node.Kind = NodeConstructor
+	}
+	if !g.add(node) {
+		return node.ID
+	}
+
+	if r.fn.IsValid() {
+		ft := r.fn.Type()
+		for i := 0; i < ft.NumIn(); i++ {
+			ptag := NewTag().SetSymbol(TagInvoke)
+			var val string
+			if i < len(r.params) {
+				// the param tags are parsed strictly by Constructor
+				val = r.params[i]
+				ptag.Unmarshal(val)
+			}
+			to := c.graph(g, ft.In(i), ptag)
+			g.Edges = append(g.Edges, GraphEdge{From: node.ID, To: to, Field: "param" + strconv.Itoa(i), Tag: val})
+			ptag.Free()
+		}
+	}
+
+	c.graphFields(g, node.ID, r.t)
+	return node.ID
+}
+
+// graphFields is adding the edges of the dix tag fields of struct type by its plan, pointer type is dereference;
+// The field with invalid dix tag has the edge to its invalid node
+func (c *Container) graphFields(g *Graph, from string, t reflect.Type) {
+	s := deref(t)
+	if s.Kind() != reflect.Struct {
+		return
+	}
+
+	p := planOf(s)
+	for i := range p.fields {
+		f := &p.fields[i]
+		if !f.set {
+			continue
+		}
+
+		var to string
+		switch {
+		case f.err != nil:
+			node := GraphNode{ID: "invalid:" + typeName(s) + "." + f.sf.Name, Kind: NodeInvalid, Label: f.err.Error()}
+			g.add(node)
+			to = node.ID
+		case f.resolve != nil:
+			to = c.graph(g, f.sf.Type, f.tag)
+		default:
+			continue
+		}
+		g.Edges = append(g.Edges, GraphEdge{From: from, To: to, Field: f.sf.Name, Tag: f.val})
+	}
+}
+
+// add is adding the node if not exists, return false if exists
+func (g *Graph) add(node GraphNode) bool {
+	if _, ok := g.index[node.ID]; ok {
+		return false
+	}
+	g.index[node.ID] = len(g.Nodes)
+	g.Nodes = append(g.Nodes, node)
+	return true
+}
+
+// namespaces return the nodes id grouped by namespace, the nodes without namespace use the empty key
+func (g *Graph) namespaces() (keys []string, groups map[string][]int) {
+	groups = make(map[string][]int)
+	for i, node := range g.Nodes {
+		if _, ok := groups[node.Namespace]; !ok {
+			keys = append(keys, node.Namespace)
+		}
+		groups[node.Namespace] = append(groups[node.Namespace], i)
+	}
+	sort.Strings(keys)
+	return keys, groups
+}
+
+// WriteDOT is writing the Graph as Graphviz DOT, the namespaces are clusters
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph dix {\n\trankdir=LR;\n\tnode [shape=box];\n")
+
+	keys, groups := g.namespaces()
+	for i, ns := range keys {
+		indent := "\t"
+		if ns != "" {
+			fmt.Fprintf(&b, "\tsubgraph cluster_%d {\n\t\tlabel=%s;\n", i, strconv.Quote("namespace "+ns))
+			indent = "\t\t"
+		}
+		for _, n := range groups[ns] {
+			node := g.Nodes[n]
+			fmt.Fprintf(&b, "%s%s [label=%s];\n", indent, strconv.Quote(node.ID), strconv.Quote(node.Kind+"\n"+node.Label))
+		}
+		if ns != "" {
+			b.WriteString("\t}\n")
+		}
+	}
+
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "\t%s -> %s [label=%s];\n", strconv.Quote(edge.From), strconv.Quote(edge.To), strconv.Quote(edgeLabel(edge)))
+	}
+
+	b.WriteString("}\n")
+	_, e := io.WriteString(w, b.String())
+	return e
+}
+
+// WriteMermaid is writing the Graph as Mermaid flowchart, the namespaces are subgraphs
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+
+	keys, groups := g.namespaces()
+	for i, ns := range keys {
+		indent := "    "
+		if ns != "" {
+			fmt.Fprintf(&b, "    subgraph ns%d [\"namespace %s\"]\n", i, mermaidEscape(ns))
+			indent = "        "
+		}
+		for _, n := range groups[ns] {
+			node := g.Nodes[n]
+			fmt.Fprintf(&b, "%sn%d[\"%s<br/>%s\"]\n", indent, n, node.Kind, mermaidEscape(node.Label))
+		}
+		if ns != "" {
+			b.WriteString("    end\n")
+		}
+	}
+
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "    n%d -->|\"%s\"| n%d\n", g.index[edge.From], mermaidEscape(edgeLabel(edge)), g.index[edge.To])
+	}
+
+	_, e := io.WriteString(w, b.String())
+	return e
+}
+
+// WriteJSON is writing the Graph as indented JSON
+func (g *Graph) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(g)
+}
+
+// Write is writing the Graph with the format `dot`, `mermaid` or `json`
+func (g *Graph) Write(w io.Writer, format string) error {
+	switch format {
+	case "dot":
+		return g.WriteDOT(w)
+	case "mermaid":
+		return g.WriteMermaid(w)
+	case "json":
+		return g.WriteJSON(w)
+	default:
+		return fmt.Errorf("graph format `%s` is not supported", format)
+	}
+}
+
+// edgeLabel return the edge label like `Field dix:"from:?"`
+func edgeLabel(edge GraphEdge) string {
+	if edge.Tag == "" {
+		return edge.Field
+	}
+	return edge.Field + " " + TagDix + ":" + edge.Tag
+}
+
+// mermaidEscape is escaping the mermaid label
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(s)
+}
+
+// Environment variables of graph export, set by cmd/dixgraph
+const (
+	EnvGraphName   = "DIXGRAPH_NAME"
+	EnvGraphFormat = "DIXGRAPH_FORMAT"
+	EnvGraphOut    = "DIXGRAPH_OUT"
+)
+
+var graphs sync.Map // graphs is map[string]func() *Graph, the registered graphs
+
+// RegisterGraph is registering the graph of the root types in the Container by name, see ExportGraph
+func RegisterGraph(name string, c *Container, types ...reflect.Type) {
+	graphs.Store(name, func() *Graph { return c.Graph(types...) })
+}
+
+// ExportGraph is writing the registered graph named by the environment variables, it is called in a test binary
+// run by cmd/dixgraph, like:
+//
+//	func TestDixGraph(t *testing.T) {
+//		dix.RegisterGraph("app", c, reflect.TypeOf(App{}))
+//		if err := dix.ExportGraph(); err != nil {
+//			t.Fatal(err)
+//		}
+//	}
+//
+// If the environment variable DIXGRAPH_OUT is not set it does nothing
+func ExportGraph() error {
+	out := os.Getenv(EnvGraphOut)
+	if out == "" {
+		return nil
+	}
+
+	name, format := os.Getenv(EnvGraphName), os.Getenv(EnvGraphFormat)
+	if format == "" {
+		format = "dot"
+	}
+
+	x, ok := graphs.Load(name)
+	if !ok {
+		return fmt.Errorf("graph `%s` is not registered", name)
+	}
+
+	f, e := os.Create(out)
+	if e != nil {
+		return e
+	}
+	if e = x.(func() *Graph)().Write(f, format); e != nil {
+		_ = f.Close()
+		return e
+	}
+	return f.Close()
+}
@@ -0,0 +1,110 @@
+package dix
+
+import (
+	"bytes"
+	"encoding/json"
+	htemplate "html/template"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	ttemplate "text/template"
+)
+
+type TGraph struct {
+	TB   TB      `dix:"from:TBP"`
+	Repo *TRepo  `dix:"from:?;namespace:ns1"`
+	Miss int     `dix:"from:miss"`
+	Self *TGraph `dix:"from:?"`
+	Bad  int     `dix:"from:?;slice_len:x"`
+}
+
+func TestGraph(t *testing.T) {
+	c := NewContainer()
+	BindIn[Provider](c, TBProvider{})
+	BindIn[TConfig](c, TConfig{}, "ns1")
+	_ = c.Constructor(func(cfg TConfig) *TRepo { return &TRepo{Config: cfg} }, WithNamespace("ns1"), WithParamTags("namespace:ns1"))
+
+	pkg := reflect.TypeOf(TGraph{}).PkgPath()
+	g := c.Graph(reflect.TypeOf(TGraph{}))
+	if len(g.Roots) != 1 || g.Roots[0] != pkg+".TGraph" {
+		t.Fatalf("graph roots unexpected: %v", g.Roots)
+	}
+
+	kinds := map[string]string{}
+	for _, node := range g.Nodes {
+		kinds[node.ID] = node.Kind
+	}
+	want := map[string]string{
+		"provider:TBP@def":               NodeProvider,
+		"provider:miss@def":              NodeMissing,
+		"*" + pkg + ".TRepo@ns1":         NodeConstructor,
+		pkg + ".TConfig@ns1":             NodeBinding,
+		"*" + pkg + ".TGraph":            NodeType,
+		"invalid:" + pkg + ".TGraph.Bad": NodeInvalid,
+	}
+	for id, kind := range want {
+		if kinds[id] != kind {
+			t.Fatalf("graph node %s unexpected: %v", id, kinds)
+		}
+	}
+
+	var dot, mermaid, js bytes.Buffer
+	for format, w := range map[string]*bytes.Buffer{"dot": &dot, "mermaid": &mermaid, "json": &js} {
+		if err := g.Write(w, format); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !strings.Contains(dot.String(), `"*`+pkg+`.TRepo@ns1" -> "`+pkg+`.TConfig@ns1" [label="param0 dix:namespace:ns1"];`) {
+		t.Fatalf("graph dot unexpected:\n%s", dot.String())
+	}
+	if !strings.Contains(dot.String(), `"`+pkg+`.TGraph" -> "invalid:`+pkg+`.TGraph.Bad" [label="Bad dix:from:?;slice_len:x"];`) {
+		t.Fatalf("graph dot invalid tag unexpected:\n%s", dot.String())
+	}
+	if !strings.HasPrefix(mermaid.String(), "flowchart LR\n") || !strings.Contains(mermaid.String(), "subgraph") {
+		t.Fatalf("graph mermaid unexpected:\n%s", mermaid.String())
+	}
+	var x Graph
+	if err := json.Unmarshal(js.Bytes(), &x); err != nil || len(x.Nodes) != len(g.Nodes) || len(x.Edges) != len(g.Edges) {
+		t.Fatalf("graph json unexpected: %v\n%s", err, js.String())
+	}
+
+	// the same-named types of different packages are distinct nodes
+	g = c.Graph(reflect.TypeOf(htemplate.Template{}), reflect.TypeOf(ttemplate.Template{}))
+	if len(g.Nodes) != 2 || g.Nodes[0].ID != "html/template.Template" || g.Nodes[1].ID != "text/template.Template" {
+		t.Fatalf("graph same-named nodes unexpected: %+v", g.Nodes)
+	}
+}
+
+func TestDixGraph(t *testing.T) {
+	c := NewContainer()
+	BindIn[Provider](c, TBProvider{})
+	RegisterGraph("dix", c, reflect.TypeOf(TGraph{}))
+
+	// run by cmd/dixgraph the environment is set, otherwise export the graph to a temp file
+	out := os.Getenv(EnvGraphOut)
+	if out == "" {
+		out = filepath.Join(t.TempDir(), "dix.dot")
+		t.Setenv(EnvGraphName, "dix")
+		t.Setenv(EnvGraphFormat, "dot")
+		t.Setenv(EnvGraphOut, out)
+	}
+	if err := ExportGraph(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if os.Getenv(EnvGraphFormat) == "dot" && !strings.Contains(string(b), `"`+reflect.TypeOf(TGraph{}).PkgPath()+`.TGraph" -> "provider:TBP@def"`) {
+		t.Fatalf("graph export unexpected:\n%s", b)
+	}
+
+	// the graph not registered is an error
+	t.Setenv(EnvGraphName, "none")
+	if err = ExportGraph(); err == nil {
+		t.Fatal("export of the unregistered graph expected error")
+	}
+}
@@ -0,0 +1,121 @@
+package dix
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// invokeTag is the empty tag passed to Tracer for Invoke, it is read-only
+var invokeTag = &Tag{x: make(map[string]string)}
+
+// Invoke is calling fn with the parameters injected by the default Container, see Container.Invoke
+func Invoke(ctx context.Context, fn any, opts ...Option) error {
+	return defContainer.Invoke(ctx, fn, opts...)
+}
+
+// Invoke is calling fn with the parameters injected, fn is like `func(srv *Server) error` or `func(srv *Server)`;
+// Each parameter is resolved by di with the tag of WithParamTags, if not set use `from:?` in the default namespace,
+// the error result of fn is returned
+func (c *Container) Invoke(ctx context.Context, fn any, opts ...Option) (e error) {
+	f := reflect.ValueOf(fn)
+	if f.Kind() != reflect.Func || f.IsNil() {
+		return fmt.Errorf("invoke `%T` is not a function", fn)
+	}
+
+	ft := f.Type()
+	if ft.NumOut() > 1 || ft.NumOut() == 1 && ft.Out(0) != typeError {
+		return fmt.Errorf("invoke `%s` must return nothing or error", ft)
+	}
+
+	o := newOptions(opts...)
+	if len(o.params) > ft.NumIn() {
+		return fmt.Errorf("invoke `%s` has %d params but %d param tags", ft, ft.NumIn(), len(o.params))
+	}
+	for i, val := range o.params {
+		if _, e := ParseTag(val); e != nil {
+			return fmt.Errorf("invoke `%s` param %d: %w", ft, i, e)
+		}
+	}
+
+	if tr := c.tracer(); tr != nil {
+		start := time.Now()
+		ctx = tr.OnResolveStart(ctx, ft, invokeTag)
+		defer func() { tr.OnResolveEnd(ctx, ft, time.Since(start), e) }()
+	}
+
+	rs := newResolver(ctx)
+	defer rs.release()
+
+	in, e := c.args(ctx, rs, nil, ft, o.params)
+	if e == nil {
+		e = rs.settle()
+	}
+	if e != nil {
+		return fmt.Errorf("invoke %w", e)
+	}
+
+	if out := call(f, in); len(out) == 1 && !out[0].IsNil() {
+		return out[0].Interface().(error)
+	}
+
+	return nil
+}
+
+// args is resolving the parameters of function type by di with the parameters dix tag,
+// if t is not nil the parameters are resolved on the path of the constructor type t
+func (c *Container) args(ctx context.Context, rs *resolver, t, ft reflect.Type, params []string) ([]reflect.Value, error) {
+	ex := rs.ex
+	defer func() { rs.ex = ex }()
+
+	in := make([]reflect.Value, ft.NumIn())
+	for i := range in {
+		var pex *Explanation
+		if ex != nil {
+			pex = &Explanation{Type: ft.In(i).String(), Field: "param" + strconv.Itoa(i), Source: SourceUnresolved}
+			if i < len(params) {
+				pex.Tag = params[i]
+			}
+			ex.Fields = append(ex.Fields, pex)
+		}
+
+		tag := NewTag().SetSymbol(TagInvoke)
+		if i < len(params) {
+			tag.Unmarshal(params[i])
+		}
+
+		secret := tag.GetSecret()
+		if t != nil {
+			rs.push(frame{t: t, param: i})
+		}
+		rs.ex = pex
+		x, e := c.di(ctx, rs, ft.In(i), tag)
+		if t != nil {
+			rs.pop()
+		}
+		tag.Free()
+		if pex != nil {
+			pex.resolved(c.format(x, nil, secret), e)
+		}
+
+		switch {
+		case e != nil:
+			return nil, fmt.Errorf("`%s` param %d `%s` di error: %w", ft, i, ft.In(i), e)
+		case x.IsValid():
+			in[i] = x
+		default:
+			in[i] = reflect.Zero(ft.In(i))
+		}
+	}
+	return in, nil
+}
+
+// call is calling the function, the variadic function use the last parameter as slice
+func call(fn reflect.Value, in []reflect.Value) []reflect.Value {
+	if fn.Type().IsVariadic() {
+		return fn.CallSlice(in)
+	}
+	return fn.Call(in)
+}
@@ -0,0 +1,37 @@
+package dix
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestInvoke(t *testing.T) {
+	ctx := context.Background()
+
+	c := NewContainer()
+	BindIn[Provider](c, TBProvider{})
+	BindIn[TConfig](c, TConfig{DSN: "ns1"}, "ns1")
+
+	var got TConfig
+	var tb TB
+	err := c.Invoke(ctx, func(cfg TConfig, x TB) error {
+		got, tb = cfg, x
+		return nil
+	}, WithParamTags("namespace:ns1", "from:TBP"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.DSN != "ns1" || tb.Int8 != 100 {
+		t.Fatalf("invoke params unexpected: %+v %+v", got, tb)
+	}
+
+	errRun := errors.New("run error")
+	if err = c.Invoke(ctx, func(*TRepo) error { return errRun }); !errors.Is(err, errRun) {
+		t.Fatalf("invoke error unexpected: %v", err)
+	}
+
+	if err = c.Invoke(ctx, func() int { return 0 }); err == nil {
+		t.Fatal("invoke must return nothing or error")
+	}
+}
@@ -0,0 +1,201 @@
+package dix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"syscall"
+	"time"
+)
+
+// Initializer is called after the fields of the instance are injected
+type Initializer interface {
+	Init(context.Context) error
+}
+
+// Closer is called by Container.Close on the instances created by the Container
+type Closer interface {
+	Close(context.Context) error
+}
+
+// Service is the background service managed by the Container, started in dependency order and stopped in reverse
+type Service interface {
+	Start(context.Context) error
+	Stop(context.Context) error
+}
+
+// DefStopTimeout is the timeout of stopping and closing when Run returns
+var DefStopTimeout = 30 * time.Second
+
+// initialize is calling the Initializer of the value
+func initialize(ctx context.Context, v reflect.Value) error {
+	if !v.CanInterface() {
+		return nil
+	}
+	if x, ok := v.Interface().(Initializer); ok {
+		if e := x.Init(ctx); e != nil {
+			return fmt.Errorf("`%s` init error: %w", v.Type(), e)
+		}
+	}
+	return nil
+}
+
+// own is recording the instance need lifecycle management, the dependencies are always recorded before the dependents
+func (c *Container) own(v reflect.Value) {
+	if v.IsValid() && v.CanInterface() {
+		c.Own(v.Interface())
+	}
+}
+
+// Own is recording the instance created out of the Container need lifecycle management,
+// the Closer is closed by Close and the Service is started by Start, like the instances created by the Container
+func (c *Container) Own(x any) {
+	if _, ok := x.(Closer); ok {
+		c.omu.Lock()
+		c.owned = append(c.owned, x)
+		c.omu.Unlock()
+	}
+	if s, ok := x.(Service); ok {
+		c.manage(s)
+	}
+}
+
+// manage is recording the Service, the dependencies are always recorded before the dependents
+func (c *Container) manage(s Service) {
+	c.omu.Lock()
+	c.services = append(c.services, s)
+	c.omu.Unlock()
+}
+
+// Close is calling Close of all instances created by the default Container, see Container.Close
+func Close(ctx context.Context) error {
+	return defContainer.Close(ctx)
+}
+
+// Close is calling Close of all instances owned by the Container in reverse dependency order;
+// The Container owns the Singleton and Scoped instances it creates, the values recorded by Own and the instances
+// created by Run; The Transient instances and the instances invoked with reflect by DI and Inject are owned by the
+// caller, they are created per DI so recording them would grow without limit, the caller can record them by Own;
+// The values of Provider and the Inject target are owned by the caller;
+// If ctx is done, the remaining instances are not closed and the ctx error is returned
+func (c *Container) Close(ctx context.Context) error {
+	c.omu.Lock()
+	owned := c.owned
+	c.owned = nil
+	c.omu.Unlock()
+
+	var errs []error
+	for i := len(owned) - 1; i >= 0; i-- {
+		if e := ctx.Err(); e != nil {
+			errs = append(errs, fmt.Errorf("close interrupted with %d instances remaining: %w", i+1, e))
+			break
+		}
+		if x, ok := owned[i].(Closer); ok {
+			if e := x.Close(ctx); e != nil {
+				errs = append(errs, fmt.Errorf("`%T` close error: %w", x, e))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Start is starting the Service instances created by the default Container, see Container.Start
+func Start(ctx context.Context) error {
+	return defContainer.Start(ctx)
+}
+
+// Start is starting the Service instances created by the Container and not started, in dependency order;
+// If a Service start error, the later Service are not started and the error is returned
+func (c *Container) Start(ctx context.Context) error {
+	c.smu.Lock()
+	defer c.smu.Unlock()
+
+	for ; ; c.started++ {
+		s := c.service(c.started)
+		if s == nil {
+			return nil
+		}
+		if e := s.Start(ctx); e != nil {
+			return fmt.Errorf("`%T` start error: %w", s, e)
+		}
+	}
+}
+
+// Stop is stopping the Service instances started by the default Container, see Container.Stop
+func Stop(ctx context.Context) error {
+	return defContainer.Stop(ctx)
+}
+
+// Stop is stopping the Service instances started by the Container in reverse dependency order;
+// If ctx is done, the remaining Service are not stopped and the ctx error is returned
+func (c *Container) Stop(ctx context.Context) error {
+	c.smu.Lock()
+	defer c.smu.Unlock()
+
+	var errs []error
+	for ; c.started > 0; c.started-- {
+		if e := ctx.Err(); e != nil {
+			errs = append(errs, fmt.Errorf("stop interrupted with %d services remaining: %w", c.started, e))
+			break
+		}
+		if s := c.service(c.started - 1); s != nil {
+			if e := s.Stop(ctx); e != nil {
+				errs = append(errs, fmt.Errorf("`%T` stop error: %w", s, e))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// service is take the i-th Service, nil if out of range
+func (c *Container) service(i int) Service {
+	c.omu.Lock()
+	defer c.omu.Unlock()
+	if i < len(c.services) {
+		return c.services[i]
+	}
+	return nil
+}
+
+// Run is running the roots with the default Container, see Container.Run
+func Run(ctx context.Context, roots ...any) error {
+	return defContainer.Run(ctx, roots...)
+}
+
+// Run is injecting the roots (pointer to struct, see Inject), starting the Service instances in dependency order,
+// then blocking until ctx is done or SIGINT/SIGTERM is received, at last stopping the Service and closing the instances;
+// The roots live as long as the Container, so the Transient and invoked instances of their fields are owned too;
+// The root itself is managed if it is a Service, the stopping and closing use the DefStopTimeout, they run even if
+// the inject of a root fails
+func (c *Container) Run(ctx context.Context, roots ...any) (e error) {
+	// the instances owned by the roots injected before an error are closed too
+	defer func() {
+		sctx, cancel := context.WithTimeout(context.Background(), DefStopTimeout)
+		defer cancel()
+		e = errors.Join(e, c.Stop(sctx), c.Close(sctx))
+	}()
+
+	for _, root := range roots {
+		if e = c.populate(ctx, root, true); e != nil {
+			return
+		}
+		if s, ok := root.(Service); ok {
+			c.manage(s)
+		}
+	}
+
+	if e = c.Start(ctx); e != nil {
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+
+	return nil
+}
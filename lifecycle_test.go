@@ -0,0 +1,180 @@
+package dix
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+var tlifeEvents []string
+
+type TLifeDB struct {
+	Config TConfig `dix:"from:?"`
+}
+
+func (db *TLifeDB) Init(ctx context.Context) error {
+	tlifeEvents = append(tlifeEvents, "init db "+db.Config.DSN)
+	return nil
+}
+
+func (db *TLifeDB) Close(ctx context.Context) error {
+	tlifeEvents = append(tlifeEvents, "close db")
+	return nil
+}
+
+type TLifeRepo struct {
+	DB *TLifeDB `dix:"from:?"`
+}
+
+func (r *TLifeRepo) Init(ctx context.Context) error {
+	tlifeEvents = append(tlifeEvents, "init repo")
+	return nil
+}
+
+func (r *TLifeRepo) Close(ctx context.Context) error {
+	tlifeEvents = append(tlifeEvents, "close repo")
+	return nil
+}
+
+func TestLifecycle(t *testing.T) {
+	tlifeEvents = nil
+
+	c := NewContainer()
+	BindIn[TConfig](c, TConfig{DSN: "dsn"})
+	BindIn[*TLifeDB](c, &TLifeDB{})
+	BindIn[*TLifeRepo](c, &TLifeRepo{})
+
+	type X struct {
+		Repo1 *TLifeRepo `dix:"from:?"`
+		Repo2 *TLifeRepo `dix:"from:?"`
+	}
+	MustResolve[X](context.Background(), c)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := c.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"init db dsn", "init repo", "close repo", "close db"}
+	if len(tlifeEvents) != len(want) {
+		t.Fatalf("lifecycle events unexpected: %v", tlifeEvents)
+	}
+	for i := range want {
+		if tlifeEvents[i] != want[i] {
+			t.Fatalf("lifecycle events unexpected: %v", tlifeEvents)
+		}
+	}
+}
+
+func TestLifecycleOwned(t *testing.T) {
+	tlifeEvents = nil
+	ctx := context.Background()
+
+	c := NewContainer()
+	BindIn[TConfig](c, TConfig{DSN: "dsn"})
+	BindWith[*TLifeDB](c, &TLifeDB{}, WithLifetime(Transient))
+	type X struct {
+		Repo *TLifeRepo `dix:"from:?"`
+	}
+
+	// the Transient and invoked instances are owned by the caller, the per-request DI do not grow the Container
+	for i := 0; i < 1000; i++ {
+		MustResolve[*TLifeDB](ctx, c)
+		MustResolve[X](ctx, c)
+	}
+	if len(c.owned) != 0 || len(c.services) != 0 {
+		t.Fatalf("owned unexpected: %d, %d", len(c.owned), len(c.services))
+	}
+
+	// the caller record them by Own
+	c.Own(MustResolve[*TLifeDB](ctx, c))
+	tlifeEvents = nil
+	if err := c.Close(ctx); err != nil || len(tlifeEvents) != 1 || tlifeEvents[0] != "close db" {
+		t.Fatalf("own close unexpected: %v, %v", err, tlifeEvents)
+	}
+}
+
+type TLifeServer struct {
+	Repo *TLifeRepo `dix:"from:?"`
+}
+
+func (s *TLifeServer) Start(ctx context.Context) error {
+	tlifeEvents = append(tlifeEvents, "start server")
+	return nil
+}
+
+func (s *TLifeServer) Stop(ctx context.Context) error {
+	tlifeEvents = append(tlifeEvents, "stop server")
+	return nil
+}
+
+type TLifeApp struct {
+	Server *TLifeServer `dix:"from:?"`
+}
+
+func (a *TLifeApp) Start(ctx context.Context) error {
+	tlifeEvents = append(tlifeEvents, "start app")
+	return nil
+}
+
+func (a *TLifeApp) Stop(ctx context.Context) error {
+	tlifeEvents = append(tlifeEvents, "stop app")
+	return nil
+}
+
+func TestRun(t *testing.T) {
+	tlifeEvents = nil
+
+	c := NewContainer()
+	BindIn[TConfig](c, TConfig{DSN: "dsn"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	app := &TLifeApp{}
+	if err := c.Run(ctx, app); err != nil {
+		t.Fatal(err)
+	}
+	if app.Server == nil || app.Server.Repo == nil || app.Server.Repo.DB == nil {
+		t.Fatalf("run inject unexpected: %+v", app)
+	}
+
+	want := []string{
+		"init db dsn", "init repo", "start server", "start app",
+		"stop app", "stop server", "close repo", "close db",
+	}
+	if len(tlifeEvents) != len(want) {
+		t.Fatalf("run events unexpected: %v", tlifeEvents)
+	}
+	for i := range want {
+		if tlifeEvents[i] != want[i] {
+			t.Fatalf("run events unexpected: %v", tlifeEvents)
+		}
+	}
+}
+
+func TestRunInjectError(t *testing.T) {
+	tlifeEvents = nil
+
+	c := NewContainer()
+	BindIn[TConfig](c, TConfig{DSN: "dsn"})
+
+	// the instances of the first root are closed when the second root inject fails
+	type X struct {
+		V int `dix:"from:none"`
+	}
+	if err := c.Run(context.Background(), &TLifeApp{}, &X{}); err == nil {
+		t.Fatal("run inject error expected")
+	}
+
+	want := []string{"init db dsn", "init repo", "close repo", "close db"}
+	if len(tlifeEvents) != len(want) {
+		t.Fatalf("run events unexpected: %v", tlifeEvents)
+	}
+	for i := range want {
+		if tlifeEvents[i] != want[i] {
+			t.Fatalf("run events unexpected: %v", tlifeEvents)
+		}
+	}
+}
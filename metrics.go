@@ -0,0 +1,206 @@
+package dix
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// latencyBuckets is the upper bounds of the latency histogram buckets, the last bucket is unbounded
+var latencyBuckets = [...]time.Duration{
+	time.Microsecond,
+	10 * time.Microsecond,
+	100 * time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+}
+
+// Metrics is the Tracer counting the resolutions per type and the provides per Provider symbol;
+// Metrics implement expvar.Var, publish it like:
+//
+//	m := dix.NewMetrics()
+//	dix.Default().SetTracer(m)
+//	expvar.Publish("dix", m)
+type Metrics struct {
+	types     sync.Map // types is map[reflect.Type]*typeMetrics
+	providers sync.Map // providers is map[string]*providerMetrics, keyed by `symbol@namespace`
+}
+
+// MetricsSnapshot is the point-in-time values of Metrics, types keyed by the type name with the full package path like
+// `*github.com/org/pkg.T` and providers keyed by `symbol@namespace`
+type MetricsSnapshot struct {
+	Types     map[string]TypeMetrics     `json:"types"`
+	Providers map[string]ProviderMetrics `json:"providers"`
+}
+
+// TypeMetrics is the resolution values of a type
+type TypeMetrics struct {
+	Resolutions uint64    `json:"resolutions"`
+	Errors      uint64    `json:"errors"`
+	CacheHits   uint64    `json:"cache_hits"`
+	Latency     Histogram `json:"latency"`
+}
+
+// ProviderMetrics is the Provide values of a Provider
+type ProviderMetrics struct {
+	Provides uint64        `json:"provides"`
+	Errors   uint64        `json:"errors"`
+	Total    time.Duration `json:"total_ns"`
+	Latency  Histogram     `json:"latency"`
+}
+
+// Histogram is the latency histogram, Counts[i] is the count of latency not greater than Bounds[i],
+// the last count is the latency greater than all bounds
+type Histogram struct {
+	Bounds []time.Duration `json:"bounds_ns"`
+	Counts []uint64        `json:"counts"`
+}
+
+// NewMetrics create an empty Metrics
+func NewMetrics() *Metrics {
+	return &Metrics{}
+}
+
+type typeMetrics struct {
+	resolutions atomic.Uint64
+	errors      atomic.Uint64
+	hits        atomic.Uint64
+	latency     histogram
+}
+
+type providerMetrics struct {
+	provides atomic.Uint64
+	errors   atomic.Uint64
+	total    atomic.Int64
+	latency  histogram
+}
+
+type histogram [len(latencyBuckets) + 1]atomic.Uint64
+
+// observe is counting the latency in its bucket
+func (h *histogram) observe(d time.Duration) {
+	i := 0
+	for i < len(latencyBuckets) && d > latencyBuckets[i] {
+		i++
+	}
+	h[i].Add(1)
+}
+
+// snapshot return the Histogram of current counts
+func (h *histogram) snapshot() Histogram {
+	x := Histogram{Bounds: append([]time.Duration(nil), latencyBuckets[:]...), Counts: make([]uint64, len(h))}
+	for i := range h {
+		x.Counts[i] = h[i].Load()
+	}
+	return x
+}
+
+// typ return the metrics of type, create if not exists
+func (m *Metrics) typ(t reflect.Type) *typeMetrics {
+	if x, ok := m.types.Load(t); ok {
+		return x.(*typeMetrics)
+	}
+	x, _ := m.types.LoadOrStore(t, &typeMetrics{})
+	return x.(*typeMetrics)
+}
+
+// provider return the metrics of Provider, create if not exists
+func (m *Metrics) provider(symbol, namespace string) *providerMetrics {
+	key := symbol + "@" + namespace
+	if x, ok := m.providers.Load(key); ok {
+		return x.(*providerMetrics)
+	}
+	x, _ := m.providers.LoadOrStore(key, &providerMetrics{})
+	return x.(*providerMetrics)
+}
+
+// OnResolveStart implement Tracer
+func (m *Metrics) OnResolveStart(ctx context.Context, t reflect.Type, tag *Tag) context.Context {
+	return ctx
+}
+
+// OnResolveEnd implement Tracer, counting the resolution of type
+func (m *Metrics) OnResolveEnd(ctx context.Context, t reflect.Type, d time.Duration, err error) {
+	x := m.typ(t)
+	x.resolutions.Add(1)
+	if err != nil {
+		x.errors.Add(1)
+	}
+	x.latency.observe(d)
+}
+
+// OnProvide implement Tracer, counting the Provide of Provider
+func (m *Metrics) OnProvide(ctx context.Context, symbol, namespace string, d time.Duration, err error) {
+	x := m.provider(symbol, namespace)
+	x.provides.Add(1)
+	if err != nil {
+		x.errors.Add(1)
+	}
+	x.total.Add(int64(d))
+	x.latency.observe(d)
+}
+
+// OnCacheHit implement CacheTracer, counting the cached instance of type
+func (m *Metrics) OnCacheHit(ctx context.Context, t reflect.Type) {
+	m.typ(t).hits.Add(1)
+}
+
+// Snapshot return the current values of Metrics
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	s := MetricsSnapshot{Types: make(map[string]TypeMetrics), Providers: make(map[string]ProviderMetrics)}
+	m.types.Range(func(k, v any) bool {
+		x := v.(*typeMetrics)
+		s.Types[typeName(k.(reflect.Type))] = TypeMetrics{
+			Resolutions: x.resolutions.Load(),
+			Errors:      x.errors.Load(),
+			CacheHits:   x.hits.Load(),
+			Latency:     x.latency.snapshot(),
+		}
+		return true
+	})
+	m.providers.Range(func(k, v any) bool {
+		x := v.(*providerMetrics)
+		s.Providers[k.(string)] = ProviderMetrics{
+			Provides: x.provides.Load(),
+			Errors:   x.errors.Load(),
+			Total:    time.Duration(x.total.Load()),
+			Latency:  x.latency.snapshot(),
+		}
+		return true
+	})
+	return s
+}
+
+// typeName return the type name with the full package path, the same-named types of different packages are distinct
+func typeName(t reflect.Type) string {
+	if t.Name() != "" {
+		if t.PkgPath() == "" {
+			return t.Name()
+		}
+		return t.PkgPath() + "." + t.Name()
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return "*" + typeName(t.Elem())
+	case reflect.Slice:
+		return "[]" + typeName(t.Elem())
+	case reflect.Array:
+		return "[" + strconv.Itoa(t.Len()) + "]" + typeName(t.Elem())
+	case reflect.Map:
+		return "map[" + typeName(t.Key()) + "]" + typeName(t.Elem())
+	default:
+		return t.String()
+	}
+}
+
+// String implement expvar.Var, return the Snapshot as JSON
+func (m *Metrics) String() string {
+	b, _ := json.Marshal(m.Snapshot())
+	return string(b)
+}
@@ -0,0 +1,65 @@
+package dix
+
+import (
+	"context"
+	"encoding/json"
+	htemplate "html/template"
+	"reflect"
+	"testing"
+	ttemplate "text/template"
+)
+
+func TestMetrics(t *testing.T) {
+	pkg := reflect.TypeOf(TB{}).PkgPath()
+	ctx := context.Background()
+	m := NewMetrics()
+	tr := &TTracer{}
+	c := NewContainer().SetTracer(MultiTracer(tr, m))
+	BindIn[Provider](c, TBProvider{})
+	BindIn[TConfig](c, TConfig{DSN: "dsn"})
+
+	type X struct {
+		TB     TB      `dix:"from:TBP"`
+		Config TConfig `dix:"from:?"`
+		Miss   TB      `dix:"from:TBP;namespace:ns1"`
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := Resolve[X](ctx, c); err == nil {
+			t.Fatal("expect error of the missing Provider")
+		}
+	}
+
+	s := m.Snapshot()
+	if x := s.Types[pkg+".TConfig"]; x.Resolutions != 3 || x.CacheHits != 2 || x.Errors != 0 {
+		t.Fatalf("type metrics unexpected: %+v", x)
+	}
+	if x := s.Types[pkg+".X"]; x.Resolutions != 3 || x.Errors != 3 {
+		t.Fatalf("type metrics unexpected: %+v", x)
+	}
+	if x := s.Providers["TBP@def"]; x.Provides != 3 || x.Errors != 0 || len(x.Latency.Counts) != len(x.Latency.Bounds)+1 {
+		t.Fatalf("provider metrics unexpected: %+v", x)
+	}
+	var n uint64
+	for _, count := range s.Types[pkg+".TB"].Latency.Counts {
+		n += count
+	}
+	if n != 6 {
+		t.Fatalf("latency histogram unexpected: %+v", s.Types[pkg+".TB"].Latency)
+	}
+	if len(tr.events) == 0 {
+		t.Fatal("multi tracer not called")
+	}
+
+	// the same-named types of different packages are distinct
+	MustResolve[*htemplate.Template](ctx, c)
+	MustResolve[*ttemplate.Template](ctx, c)
+	if s := m.Snapshot(); s.Types["*html/template.Template"].Resolutions != 1 || s.Types["*text/template.Template"].Resolutions != 1 {
+		t.Fatalf("type metrics keys unexpected: %v", s.Types)
+	}
+
+	var x MetricsSnapshot
+	if err := json.Unmarshal([]byte(m.String()), &x); err != nil || x.Types[pkg+".X"].Errors != 3 {
+		t.Fatalf("metrics json unexpected: %v %s", err, m)
+	}
+}
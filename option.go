@@ -0,0 +1,74 @@
+package dix
+
+// Lifetime is the binding lifetime
+type Lifetime int
+
+const (
+	// Singleton is resolved and field-injected exactly once, the same instance is shared by all DI
+	Singleton Lifetime = iota
+	// Transient is a fresh copy/instance resolved and field-injected on every DI
+	Transient
+	// Scoped is resolved and field-injected once per scope, the scope is the Container (or child Container) of DI
+	Scoped
+)
+
+// String return the Lifetime name
+func (l Lifetime) String() string {
+	switch l {
+	case Singleton:
+		return "singleton"
+	case Transient:
+		return "transient"
+	case Scoped:
+		return "scoped"
+	default:
+		return "unknown"
+	}
+}
+
+// Option is the binding option
+type Option func(*options)
+
+// options is the binding options
+type options struct {
+	// namespaces is the binding working spaces
+	namespaces []string
+	// lifetime is the binding lifetime
+	lifetime Lifetime
+	// params is the constructor parameters dix tag
+	params []string
+	// secret is the binding value sensitive
+	secret bool
+}
+
+// WithNamespace set the binding namespaces, if not set use the default namespace
+func WithNamespace(namespaces ...string) Option {
+	return func(o *options) { o.namespaces = append(o.namespaces, namespaces...) }
+}
+
+// WithLifetime set the binding lifetime, if not set use Singleton
+func WithLifetime(lifetime Lifetime) Option {
+	return func(o *options) { o.lifetime = lifetime }
+}
+
+// WithParamTags set the constructor parameters dix tag by position, like `namespace:ns1` or `from:xfp;kind:x1`
+func WithParamTags(tags ...string) Option {
+	return func(o *options) { o.params = append(o.params, tags...) }
+}
+
+// WithSecret set the binding value is sensitive, it is redacted in the binding log
+func WithSecret() Option {
+	return func(o *options) { o.secret = true }
+}
+
+// newOptions apply the options and fill the default values
+func newOptions(opts ...Option) *options {
+	o := &options{lifetime: Singleton}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if len(o.namespaces) == 0 {
+		o.namespaces = append(o.namespaces, DefNamespace)
+	}
+	return o
+}
@@ -0,0 +1,55 @@
+package dix
+
+import (
+	"context"
+	"testing"
+)
+
+type TLife struct {
+	TB  *TB `dix:"from:?"`
+	Int int `dix:"from:?"`
+}
+
+func TestLifetime(t *testing.T) {
+	ctx := context.Background()
+
+	c := NewContainer()
+	BindIn[int](c, 10)
+	BindWith[*TLife](c, &TLife{}, WithLifetime(Singleton))
+	BindWith[*TLife](c, &TLife{}, WithNamespace("transient"), WithLifetime(Transient))
+	BindWith[*TLife](c, &TLife{}, WithNamespace("scoped"), WithLifetime(Scoped))
+
+	type X struct {
+		Singleton1 *TLife `dix:"from:?"`
+		Singleton2 *TLife `dix:"from:?"`
+		Transient1 *TLife `dix:"from:?;namespace:transient"`
+		Transient2 *TLife `dix:"from:?;namespace:transient"`
+		Scoped1    *TLife `dix:"from:?;namespace:scoped"`
+		Scoped2    *TLife `dix:"from:?;namespace:scoped"`
+	}
+
+	x := MustResolve[X](ctx, c)
+	if x.Singleton1 != x.Singleton2 || x.Singleton1.Int != 10 || x.Singleton1.TB == nil {
+		t.Fatalf("singleton unexpected: %+v %+v", x.Singleton1, x.Singleton2)
+	}
+	if x.Transient1 == x.Transient2 || x.Transient1.Int != 10 || x.Transient2.Int != 10 {
+		t.Fatalf("transient unexpected: %+v %+v", x.Transient1, x.Transient2)
+	}
+	if x.Scoped1 != x.Scoped2 || x.Scoped1.Int != 10 {
+		t.Fatalf("scoped unexpected: %+v %+v", x.Scoped1, x.Scoped2)
+	}
+
+	child := c.Child()
+	BindIn[int](child, 20)
+
+	y := MustResolve[X](ctx, child)
+	if y.Singleton1 != x.Singleton1 || y.Singleton1.Int != 10 {
+		t.Fatalf("child singleton unexpected: %+v", y.Singleton1)
+	}
+	if y.Scoped1 == x.Scoped1 || y.Scoped1 != y.Scoped2 || y.Scoped1.Int != 20 {
+		t.Fatalf("child scoped unexpected: %+v", y.Scoped1)
+	}
+	if y.Transient1.Int != 20 {
+		t.Fatalf("child transient unexpected: %+v", y.Transient1)
+	}
+}
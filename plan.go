@@ -0,0 +1,66 @@
+package dix
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+var plans sync.Map // plans is map[reflect.Type]*plan, the compiled injection plans
+
+// plan is the compiled injection plan of a struct type, it is immutable once compiled and shared by all Container
+type plan struct {
+	fields []field // fields is the dix tag fields in declaration order
+}
+
+// field is the compiled dix tag field of plan
+type field struct {
+	index   int                 // index is the field index of struct
+	sf      reflect.StructField // sf is the struct field
+	val     string              // val is the dix tag value
+	tag     *Tag                // tag is the parsed dix tag, it is read-only
+	err     error               // err is the TagError of the dix tag, the field is never resolved
+	set     bool                // set is the field exported and can be injected
+	resolve resolveFunc         // resolve is resolving the field value, nil is never resolved
+}
+
+// resolveFunc is resolving the field value with the Container
+type resolveFunc func(c *Container, ctx context.Context, rs *resolver) (reflect.Value, error)
+
+// planOf return the compiled plan of struct type, compile on the first call
+func planOf(t reflect.Type) *plan {
+	if p, ok := plans.Load(t); ok {
+		return p.(*plan)
+	}
+	p, _ := plans.LoadOrStore(t, compile(t))
+	return p.(*plan)
+}
+
+// compile is parsing the dix tags of struct type and choosing the resolver of each field
+func compile(t reflect.Type) *plan {
+	p := &plan{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		val, ok := sf.Tag.Lookup(TagDix)
+		if !ok {
+			continue
+		}
+
+		// the tag is not from the pool, the plan keeps it; the invalid tag is kept parsed leniently for the logs
+		tag, err := ParseTag(val)
+		if err != nil {
+			tag = (&Tag{x: make(map[string]string)}).Unmarshal(val)
+		}
+		f := field{index: i, sf: sf, val: val, tag: tag, err: err, set: sf.IsExported()}
+
+		// the field without symbol or with invalid tag is never resolved
+		if err == nil && tag.GetSymbol() != "" {
+			ft := sf.Type
+			f.resolve = func(c *Container, ctx context.Context, rs *resolver) (reflect.Value, error) {
+				return c.di(ctx, rs, ft, tag)
+			}
+		}
+		p.fields = append(p.fields, f)
+	}
+	return p
+}
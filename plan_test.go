@@ -0,0 +1,61 @@
+package dix
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestPlan(t *testing.T) {
+	type X struct {
+		Int     int `dix:"from:?;namespace:ns1"`
+		NoTag   int
+		NoFrom  int `dix:"namespace:ns1"`
+		private int `dix:"from:?"`
+		TB      TB  `dix:"from:TBP;kind:x1"`
+	}
+
+	p := planOf(reflect.TypeOf(X{}))
+	if p != planOf(reflect.TypeOf(X{})) || len(p.fields) != 4 {
+		t.Fatalf("plan unexpected: %+v", p)
+	}
+
+	want := []struct {
+		index   int
+		set     bool
+		resolve bool
+		ns      string
+	}{
+		{0, true, true, "ns1"},
+		{2, true, false, "ns1"},
+		{3, false, true, ""},
+		{4, true, true, ""},
+	}
+	for i, w := range want {
+		f := p.fields[i]
+		if f.index != w.index || f.set != w.set || (f.resolve != nil) != w.resolve || f.tag.GetNamespace() != w.ns {
+			t.Fatalf("plan field %d unexpected: %+v", i, f)
+		}
+	}
+	if kind, _ := p.fields[3].tag.GetCustomize("kind"); kind != "x1" {
+		t.Fatalf("plan field tag unexpected: %s", p.fields[3].tag.Marshal())
+	}
+
+	// the plan is shared by the concurrent DI
+	c := NewContainer()
+	BindIn[Provider](c, TBProvider{})
+	BindIn[int](c, 10, "ns1")
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			x, err := Resolve[X](context.Background(), c)
+			if err != nil || x.Int != 10 || x.TB.Int8 != 100 || x.NoFrom != 0 {
+				t.Errorf("resolve unexpected: %+v %v", x, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,6 @@
+//go:build race
+
+package dix
+
+// race is the race detector enabled, it allocates in the reflect calls
+const race = true
@@ -0,0 +1,231 @@
+package dix
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Redacted is the value shown in logs and explanations instead of the sensitive value
+const Redacted = "***"
+
+var typeRedactor = reflect.TypeOf((*Redactor)(nil)).Elem()
+
+// Redactor is implemented by the sensitive types, Redact return the value shown in logs and explanations
+type Redactor interface {
+	Redact() string
+}
+
+// RedactPolicy is deciding the value is sensitive, t is the value type and sf is the struct field (nil if not a field)
+type RedactPolicy func(t reflect.Type, sf *reflect.StructField) bool
+
+// RedactFields return the RedactPolicy of the struct field names containing any of the words, case-insensitive
+func RedactFields(words ...string) RedactPolicy {
+	words = append([]string(nil), words...)
+	for i := range words {
+		words[i] = strings.ToLower(words[i])
+	}
+	return func(t reflect.Type, sf *reflect.StructField) bool {
+		if sf == nil {
+			return false
+		}
+		name := strings.ToLower(sf.Name)
+		for _, word := range words {
+			if strings.Contains(name, word) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// SetRedactPolicy set the RedactPolicy of the Container, the values decided sensitive are redacted in logs and explanations;
+// The `secret:true` dix tag and the Redactor types are always redacted, if not set the child Container use the policy of parent
+func (c *Container) SetRedactPolicy(p RedactPolicy) *Container {
+	c.redact.Store(&p)
+	return c
+}
+
+// redactPolicy return the RedactPolicy of the Container, walk the chain child to parent
+func (c *Container) redactPolicy() RedactPolicy {
+	for x := c; x != nil; x = x.parent {
+		if p := x.redact.Load(); p != nil && *p != nil {
+			return *p
+		}
+	}
+	return nil
+}
+
+// format return the value shown in logs and explanations, the sensitive values are redacted
+func (c *Container) format(v reflect.Value, sf *reflect.StructField, secret bool) string {
+	if !v.IsValid() {
+		return ""
+	}
+	r := &redactor{policy: c.redactPolicy(), sensitive: make(map[reflect.Type]sensitivity)}
+	return r.format(v, sf, secret, 0)
+}
+
+// sensitivity is the type containing sensitive values or not
+type sensitivity uint8
+
+const (
+	// insensitive is the type never containing sensitive values
+	insensitive sensitivity = iota
+	// dynamic is the type containing interface values, it is sensitive if the dynamic values are
+	dynamic
+	// sensitive is the type always containing sensitive values
+	sensitive
+)
+
+// redactor is formatting the values with redaction
+type redactor struct {
+	policy    RedactPolicy
+	sensitive map[reflect.Type]sensitivity // sensitive is the sensitivity of types, insensitive while checking
+}
+
+// format is formatting the value like `%#v`, the sensitive values are redacted
+func (r *redactor) format(v reflect.Value, sf *reflect.StructField, secret bool, depth int) string {
+	if !v.IsValid() {
+		return "<nil>"
+	}
+	if secret || r.policy != nil && r.policy(v.Type(), sf) {
+		return Redacted
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return fmt.Sprintf("%#v", v)
+		}
+	}
+	// past the depth limit the value is not walked, it is redacted if it may contain sensitive values
+	if depth > 16 {
+		if r.contains(v.Type()) != insensitive {
+			return Redacted
+		}
+		return fmt.Sprintf("%#v", v)
+	}
+	if !r.holds(v, depth) {
+		return fmt.Sprintf("%#v", v)
+	}
+
+	if v.CanAddr() && v.Addr().CanInterface() {
+		if x, ok := v.Addr().Interface().(Redactor); ok {
+			return x.Redact()
+		}
+	}
+	if v.CanInterface() {
+		if x, ok := v.Interface().(Redactor); ok {
+			return x.Redact()
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		return "&" + r.format(v.Elem(), nil, false, depth+1)
+	case reflect.Interface:
+		return r.format(v.Elem(), nil, false, depth+1)
+	case reflect.Struct:
+		t := v.Type()
+		fields := make([]string, t.NumField())
+		for i := range fields {
+			f := t.Field(i)
+			fields[i] = f.Name + ":" + r.format(v.Field(i), &f, fieldSecret(&f), depth+1)
+		}
+		return t.String() + "{" + strings.Join(fields, ", ") + "}"
+	case reflect.Slice, reflect.Array:
+		elems := make([]string, v.Len())
+		for i := range elems {
+			elems[i] = r.format(v.Index(i), nil, false, depth+1)
+		}
+		return v.Type().String() + "{" + strings.Join(elems, ", ") + "}"
+	default:
+		return Redacted
+	}
+}
+
+// contains is checking the type contains sensitive values, by Redactor, `secret:true` dix tag or RedactPolicy;
+// The interface types are dynamic, their values are checked by holds
+func (r *redactor) contains(t reflect.Type) sensitivity {
+	if x, ok := r.sensitive[t]; ok {
+		return x
+	}
+	if t.Implements(typeRedactor) || reflect.PointerTo(t).Implements(typeRedactor) {
+		r.sensitive[t] = sensitive
+		return sensitive
+	}
+
+	// the recursive type is insensitive while checking
+	r.sensitive[t] = insensitive
+
+	var x sensitivity
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		x = r.contains(t.Elem())
+	case reflect.Interface:
+		x = dynamic
+	case reflect.Struct:
+		for i := 0; i < t.NumField() && x != sensitive; i++ {
+			if f := t.Field(i); fieldSecret(&f) || r.policy != nil && r.policy(f.Type, &f) {
+				x = sensitive
+			} else {
+				x = max(x, r.contains(f.Type))
+			}
+		}
+	}
+
+	r.sensitive[t] = x
+	return x
+}
+
+// holds is checking the value contains sensitive values, only the dynamic values of the interfaces are walked;
+// Past the depth limit the value may contain sensitive values unless its type is insensitive
+func (r *redactor) holds(v reflect.Value, depth int) bool {
+	if !v.IsValid() {
+		return false
+	}
+	if depth > 16 {
+		return r.contains(v.Type()) != insensitive
+	}
+	switch r.contains(v.Type()) {
+	case insensitive:
+		return false
+	case sensitive:
+		return true
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return !v.IsNil() && r.holds(v.Elem(), depth+1)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if r.holds(v.Field(i), depth+1) {
+				return true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if r.holds(v.Index(i), depth+1) {
+				return true
+			}
+		}
+	case reflect.Map:
+		for it := v.MapRange(); it.Next(); {
+			if r.holds(it.Value(), depth+1) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fieldSecret is checking the struct field has the `secret:true` dix tag
+func fieldSecret(sf *reflect.StructField) bool {
+	val, ok := sf.Tag.Lookup(TagDix)
+	if !ok {
+		return false
+	}
+	tag := NewTag(val)
+	defer tag.Free()
+	return tag.GetSecret()
+}
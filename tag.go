@@ -1,7 +1,10 @@
 package dix
 
 import (
+	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
 )
 
@@ -23,6 +26,8 @@ var (
 	TagSymbol = "from"
 	// TagNamespace set the di working space
 	TagNamespace = "namespace"
+	// TagSecret set the value is sensitive, it is redacted in logs and explanations
+	TagSecret = "secret"
 	// TagInvoke invoke type and set zero value
 	TagInvoke = "?"
 )
@@ -46,6 +51,8 @@ type Tag struct {
 	len int
 	// cap is slice cap
 	cap int
+	// secret is the value sensitive
+	secret bool
 	// x is custom Tags
 	x map[string]string
 }
@@ -60,6 +67,16 @@ func (tag *Tag) Free() {
 	poolTag.Put(tag)
 }
 
+// clone return a copy of the tag from the pool, Free it after use
+func (tag *Tag) clone() *Tag {
+	x := poolTag.Get().(*Tag)
+	x.namespace, x.symbol, x.buff, x.size, x.len, x.cap, x.secret = tag.namespace, tag.symbol, tag.buff, tag.size, tag.len, tag.cap, tag.secret
+	for k, v := range tag.x {
+		x.x[k] = v
+	}
+	return x
+}
+
 func (tag *Tag) Reset() *Tag {
 	tag.namespace = ""
 	tag.symbol = ""
@@ -67,62 +84,219 @@ func (tag *Tag) Reset() *Tag {
 	tag.size = 0
 	tag.len = 0
 	tag.cap = 0
+	tag.secret = false
 	for k := range tag.x {
 		delete(tag.x, k)
 	}
 	return tag
 }
 
+// Unmarshal is parsing the dix tags leniently, the pairs after a syntax error and the invalid values are ignored;
+// Use ParseTag to get the errors, the grammar is SplitTag
 func (tag *Tag) Unmarshal(x ...string) *Tag {
 	for _, f := range x {
-		var k, v string
-		var idx int
-		for i := 0; i < len(f); i++ {
-			if f[i] == ':' {
-				k = f[idx:i]
-				idx = i + 1
-				for x := idx; x < len(f); x++ {
-					if f[x] == ';' {
-						v = f[idx:x]
-						tag.set(k, v)
-						k, v = "", ""
-						idx = x + 1
-						i = x
-						break
-					} else if x == len(f)-1 {
-						v = f[idx:]
-						tag.set(k, v)
-						i = x
-						break
-					}
+		_ = scanTag(f, func(p TagPair) error {
+			_ = tag.set(p.Key, p.Value)
+			return nil
+		})
+	}
+	return tag
+}
+
+// ParseTag is parsing the dix tag strictly, the error is TagError of the first problem;
+// The syntax is SplitTag, the keys are not repeated, the integer keys are non-negative integer, slice_cap is not less
+// than slice_len and secret is bool;
+// The Tag is not from the pool, Free is not required
+func ParseTag(s string) (*Tag, error) {
+	tag := &Tag{x: make(map[string]string)}
+	var seen []string
+	var lenOffset int
+	err := scanTag(s, func(p TagPair) error {
+		if p.Key == TagSliceLen {
+			lenOffset = p.ValueOffset
+		}
+		for _, k := range seen {
+			if k == p.Key {
+				return &TagError{Tag: s, Offset: p.Offset, Msg: "key `" + p.Key + "` is repeated"}
+			}
+		}
+		seen = append(seen, p.Key)
+
+		if e := tag.set(p.Key, p.Value); e != nil {
+			return &TagError{Tag: s, Offset: p.ValueOffset, Msg: e.Error()}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if tag.len > tag.cap {
+		msg := fmt.Sprintf("`%s` %d is less than `%s` %d", TagSliceCap, tag.cap, TagSliceLen, tag.len)
+		return nil, &TagError{Tag: s, Offset: lenOffset, Msg: msg}
+	}
+	return tag, nil
+}
+
+// TagPair is a `key:value` pair of the dix tag, Offset and ValueOffset is the byte offset of the key and value
+type TagPair struct {
+	Key         string
+	Value       string
+	Offset      int
+	ValueOffset int
+}
+
+// SplitTag is splitting the dix tag into pairs, the error is TagError and the pairs before it are returned;
+// The grammar is:
+//
+//	tag    = [ pair ] { ";" [ pair ] }
+//	pair   = key ":" value
+//	key    = 1*( any byte except ":" ";" "'" and blank )
+//	value  = quoted | raw
+//	quoted = "'" { any byte except "'" `\` | `\'` | `\\` } "'"
+//	raw    = { any byte except ";" "'" }
+//
+// The blanks around the keys and values are ignored, the quoted value keeps them and may contain ";" and ":";
+// Note the backslash of the quoted value is doubled in the Go struct tag literal, like `dix:"from:'a\\'b'"`
+func SplitTag(s string) ([]TagPair, error) {
+	var pairs []TagPair
+	err := scanTag(s, func(p TagPair) error {
+		pairs = append(pairs, p)
+		return nil
+	})
+	return pairs, err
+}
+
+// scanTag is calling fn with the pairs of the dix tag in order, it stops at the first error of syntax or fn
+func scanTag(s string, fn func(p TagPair) error) error {
+	i := 0
+	for {
+		i = skipBlank(s, i)
+		if i == len(s) {
+			return nil
+		}
+		if s[i] == ';' {
+			i++
+			continue
+		}
+
+		p := TagPair{Offset: i}
+		for i < len(s) && s[i] != ':' && s[i] != ';' && s[i] != '\'' && !isBlank(s[i]) {
+			i++
+		}
+		if i == p.Offset {
+			return &TagError{Tag: s, Offset: i, Msg: "missing key"}
+		}
+		p.Key = s[p.Offset:i]
+
+		if i = skipBlank(s, i); i == len(s) || s[i] != ':' {
+			return &TagError{Tag: s, Offset: i, Msg: "missing `:` after key `" + p.Key + "`"}
+		}
+		i = skipBlank(s, i+1)
+		p.ValueOffset = i
+
+		if i < len(s) && s[i] == '\'' {
+			v, n, e := unquoteTag(s, i)
+			if e != nil {
+				return e
+			}
+			p.Value = v
+			if i = skipBlank(s, n); i < len(s) && s[i] != ';' {
+				return &TagError{Tag: s, Offset: i, Msg: "unexpected `" + s[i:i+1] + "` after quoted value"}
+			}
+		} else {
+			for i < len(s) && s[i] != ';' {
+				if s[i] == '\'' {
+					return &TagError{Tag: s, Offset: i, Msg: "quote in raw value, quote the whole value"}
 				}
+				i++
 			}
+			p.Value = strings.TrimRight(s[p.ValueOffset:i], " \t")
+		}
+
+		if e := fn(p); e != nil {
+			return e
 		}
 	}
-	return tag
 }
 
-func (tag *Tag) set(k, v string) {
+// unquoteTag return the quoted value starting at i and the offset after the closing quote
+func unquoteTag(s string, i int) (string, int, error) {
+	var b strings.Builder
+	for j := i + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\'':
+			return b.String(), j + 1, nil
+		case '\\':
+			if j+1 == len(s) || s[j+1] != '\'' && s[j+1] != '\\' {
+				return "", 0, &TagError{Tag: s, Offset: j, Msg: "invalid escape, want `\\'` or `\\\\`"}
+			}
+			j++
+		}
+		b.WriteByte(s[j])
+	}
+	return "", 0, &TagError{Tag: s, Offset: i, Msg: "unterminated quoted value"}
+}
+
+// quoteTag return the value quoted if it can not be a raw value
+func quoteTag(v string) string {
+	if v == strings.Trim(v, " \t") && !strings.ContainsAny(v, ";'") {
+		return v
+	}
+	var b strings.Builder
+	b.WriteByte('\'')
+	for i := 0; i < len(v); i++ {
+		if v[i] == '\'' || v[i] == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(v[i])
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
+
+func isBlank(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+func skipBlank(s string, i int) int {
+	for i < len(s) && isBlank(s[i]) {
+		i++
+	}
+	return i
+}
+
+// set is setting the value of key, the error is the invalid value of the builtin keys
+func (tag *Tag) set(k, v string) error {
 	switch k {
 	case TagNamespace:
 		tag.SetNamespace(v)
 	case TagSymbol:
 		tag.SetSymbol(v)
-	case TagChanBuf:
-		v_, _ := strconv.Atoi(v)
-		tag.SetChanBuf(v_)
-	case TagMapSize:
-		v_, _ := strconv.Atoi(v)
-		tag.SetMapSize(v_)
-	case TagSliceLen:
-		v_, _ := strconv.Atoi(v)
-		tag.SetSliceLen(v_)
-	case TagSliceCap:
-		v_, _ := strconv.Atoi(v)
-		tag.SetSliceCap(v_)
+	case TagChanBuf, TagMapSize, TagSliceLen, TagSliceCap:
+		n, e := strconv.Atoi(v)
+		if e != nil || n < 0 {
+			return fmt.Errorf("`%s` value `%s` is not a non-negative integer", k, v)
+		}
+		switch k {
+		case TagChanBuf:
+			tag.SetChanBuf(n)
+		case TagMapSize:
+			tag.SetMapSize(n)
+		case TagSliceLen:
+			tag.SetSliceLen(n)
+		default:
+			tag.SetSliceCap(n)
+		}
+	case TagSecret:
+		b, e := strconv.ParseBool(v)
+		if e != nil {
+			return fmt.Errorf("`%s` value `%s` is not bool", k, v)
+		}
+		tag.SetSecret(b)
 	default:
 		tag.SetCustomize(k, v)
 	}
+	return nil
 }
 
 func (tag *Tag) SetNamespace(x string) *Tag {
@@ -155,6 +329,11 @@ func (tag *Tag) SetSliceCap(x int) *Tag {
 	return tag
 }
 
+func (tag *Tag) SetSecret(x bool) *Tag {
+	tag.secret = x
+	return tag
+}
+
 func (tag *Tag) SetCustomize(k, v string) *Tag {
 	tag.x[k] = v
 	return tag
@@ -184,7 +363,55 @@ func (tag *Tag) GetSliceCap() int {
 	return tag.cap
 }
 
+func (tag *Tag) GetSecret() bool {
+	return tag.secret
+}
+
 func (tag *Tag) GetCustomize(k string) (v string, ok bool) {
 	v, ok = tag.x[k]
 	return
 }
+
+// Marshal return the tag string of the non-zero values, the customize tags are sorted by key;
+// The values are quoted if needed, ParseTag of it is the same Tag
+func (tag *Tag) Marshal() string {
+	var b strings.Builder
+	add := func(k, v string) {
+		if b.Len() > 0 {
+			b.WriteByte(';')
+		}
+		b.WriteString(k)
+		b.WriteByte(':')
+		b.WriteString(quoteTag(v))
+	}
+	if tag.symbol != "" {
+		add(TagSymbol, tag.symbol)
+	}
+	if tag.namespace != "" {
+		add(TagNamespace, tag.namespace)
+	}
+	if tag.buff != 0 {
+		add(TagChanBuf, strconv.Itoa(tag.buff))
+	}
+	if tag.size != 0 {
+		add(TagMapSize, strconv.Itoa(tag.size))
+	}
+	if tag.len != 0 {
+		add(TagSliceLen, strconv.Itoa(tag.len))
+	}
+	if tag.cap != 0 {
+		add(TagSliceCap, strconv.Itoa(tag.cap))
+	}
+	if tag.secret {
+		add(TagSecret, "true")
+	}
+	keys := make([]string, 0, len(tag.x))
+	for k := range tag.x {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		add(k, tag.x[k])
+	}
+	return b.String()
+}
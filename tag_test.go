@@ -1,6 +1,9 @@
 package dix
 
-import "testing"
+import (
+	"errors"
+	"testing"
+)
 
 func TestTag(t *testing.T) {
 	tag := NewTag("from:?;namespace:ns1;kind:x1;slice_len:10;slice_cap:10;map_size:10;chan_buf:10")
@@ -15,3 +18,59 @@ func TestTag(t *testing.T) {
 	t.Log(tag.GetCustomize("kind"))
 	t.Log(tag.GetCustomize("x"))
 }
+
+func TestParseTag(t *testing.T) {
+	tag, err := ParseTag(`from:?; namespace: 'a;b:c' ;kind:'it\'s \\';chan_buf:2;secret:true;x:`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag.GetNamespace() != "a;b:c" || tag.GetChanBuf() != 2 || !tag.GetSecret() {
+		t.Fatalf("tag unexpected: %s", tag.Marshal())
+	}
+	if v, _ := tag.GetCustomize("kind"); v != `it's \` {
+		t.Fatalf("quoted value unexpected: %s", v)
+	}
+	if _, ok := tag.GetCustomize("x"); !ok {
+		t.Fatal("empty value at end is dropped")
+	}
+
+	// Marshal is quoted and parsed back
+	again, err := ParseTag(tag.Marshal())
+	if err != nil || again.Marshal() != tag.Marshal() {
+		t.Fatalf("marshal unexpected: %s: %v", tag.Marshal(), err)
+	}
+	t.Log(tag.Marshal())
+
+	cases := []struct {
+		tag    string
+		offset int
+		msg    string
+	}{
+		{"from:?;namespace", 16, "missing `:` after key `namespace`"},
+		{"from:?;slice_cap=3", 18, "missing `:` after key `slice_cap=3`"},
+		{":x", 0, "missing key"},
+		{"from:'x", 5, "unterminated quoted value"},
+		{`from:'x\y'`, 7, "invalid escape, want `\\'` or `\\\\`"},
+		{"from:'x' y", 9, "unexpected `y` after quoted value"},
+		{"from:it's", 7, "quote in raw value, quote the whole value"},
+		{"from:?;from:x", 7, "key `from` is repeated"},
+		{"from:?;chan_buf:ten", 16, "`chan_buf` value `ten` is not a non-negative integer"},
+		{"from:?;map_size:-1", 16, "`map_size` value `-1` is not a non-negative integer"},
+		{"from:?;secret:yes", 14, "`secret` value `yes` is not bool"},
+		{"from:?;slice_len:2", 17, "`slice_cap` 0 is less than `slice_len` 2"},
+	}
+	for _, x := range cases {
+		_, err := ParseTag(x.tag)
+		te := (*TagError)(nil)
+		if !errors.As(err, &te) || te.Offset != x.offset || te.Msg != x.msg {
+			t.Fatalf("tag `%s` error unexpected: %v", x.tag, err)
+		}
+	}
+
+	// Unmarshal is lenient, the pairs before the error are kept
+	tag = NewTag("from:?;chan_buf:ten;namespace:ns1;slice_len")
+	defer tag.Free()
+	if tag.GetSymbol() != TagInvoke || tag.GetChanBuf() != 0 || tag.GetNamespace() != "ns1" {
+		t.Fatalf("lenient tag unexpected: %s", tag.Marshal())
+	}
+}
@@ -0,0 +1,83 @@
+package dix
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// Tracer is receiving the resolution events of the Container, like OpenTelemetry spans or timing;
+// The callbacks are called synchronously on the resolving goroutine, so they should be fast
+type Tracer interface {
+	// OnResolveStart is called before the type resolved with the dix tag, the returned ctx is used by the resolution,
+	// so the nested resolutions can be children of it; The tag is read-only and used only during the call, Marshal it
+	// if the tag string is needed
+	OnResolveStart(ctx context.Context, t reflect.Type, tag *Tag) context.Context
+	// OnResolveEnd is called after the type resolved, ctx is returned by OnResolveStart
+	OnResolveEnd(ctx context.Context, t reflect.Type, d time.Duration, err error)
+	// OnProvide is called after the Provider of symbol in the namespace provided
+	OnProvide(ctx context.Context, symbol, namespace string, d time.Duration, err error)
+}
+
+// SetTracer set the Tracer of the Container, the type resolution, Provider and Invoke are traced;
+// If not set (or set nil) the child Container use the Tracer of parent, the root Container not trace
+func (c *Container) SetTracer(t Tracer) *Container {
+	c.trace.Store(&t)
+	return c
+}
+
+// tracer return the Tracer of the Container, walk the chain child to parent
+func (c *Container) tracer() Tracer {
+	for x := c; x != nil; x = x.parent {
+		if t := x.trace.Load(); t != nil && *t != nil {
+			return *t
+		}
+	}
+	return nil
+}
+
+// CacheTracer is the optional interface of Tracer, OnCacheHit is called when the Singleton or Scoped instance resolved before is used
+type CacheTracer interface {
+	OnCacheHit(ctx context.Context, t reflect.Type)
+}
+
+// cacheHit is calling the CacheTracer of the Container if set
+func (c *Container) cacheHit(ctx context.Context, t reflect.Type) {
+	if tr, ok := c.tracer().(CacheTracer); ok {
+		tr.OnCacheHit(ctx, t)
+	}
+}
+
+// MultiTracer return the Tracer calling all the tracers in order, the ctx returned by a tracer is passed to the next
+func MultiTracer(tracers ...Tracer) Tracer {
+	return multiTracer(tracers)
+}
+
+type multiTracer []Tracer
+
+func (m multiTracer) OnResolveStart(ctx context.Context, t reflect.Type, tag *Tag) context.Context {
+	for _, tr := range m {
+		ctx = tr.OnResolveStart(ctx, t, tag)
+	}
+	return ctx
+}
+
+func (m multiTracer) OnResolveEnd(ctx context.Context, t reflect.Type, d time.Duration, err error) {
+	for _, tr := range m {
+		tr.OnResolveEnd(ctx, t, d, err)
+	}
+}
+
+func (m multiTracer) OnProvide(ctx context.Context, symbol, namespace string, d time.Duration, err error) {
+	for _, tr := range m {
+		tr.OnProvide(ctx, symbol, namespace, d, err)
+	}
+}
+
+func (m multiTracer) OnCacheHit(ctx context.Context, t reflect.Type) {
+	for _, tr := range m {
+		if tr, ok := tr.(CacheTracer); ok {
+			tr.OnCacheHit(ctx, t)
+		}
+	}
+}
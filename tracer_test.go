@@ -0,0 +1,71 @@
+package dix
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type tdepth struct{}
+
+type TTracer struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (tr *TTracer) add(ctx context.Context, s string) {
+	depth, _ := ctx.Value(tdepth{}).(int)
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	for i := 0; i < depth; i++ {
+		s = "  " + s
+	}
+	tr.events = append(tr.events, s)
+}
+
+func (tr *TTracer) OnResolveStart(ctx context.Context, t reflect.Type, tag *Tag) context.Context {
+	tr.add(ctx, "start "+t.String()+" "+tag.Marshal())
+	depth, _ := ctx.Value(tdepth{}).(int)
+	return context.WithValue(ctx, tdepth{}, depth+1)
+}
+
+func (tr *TTracer) OnResolveEnd(ctx context.Context, t reflect.Type, d time.Duration, err error) {
+	tr.add(ctx, "end "+t.String())
+}
+
+func (tr *TTracer) OnProvide(ctx context.Context, symbol, namespace string, d time.Duration, err error) {
+	tr.add(ctx, "provide "+symbol+"@"+namespace)
+}
+
+func TestTracer(t *testing.T) {
+	tr := &TTracer{}
+	c := NewContainer().SetTracer(tr)
+	BindIn[Provider](c, TBProvider{})
+
+	type X struct {
+		TB  TB  `dix:"from:TBP"`
+		Int int `dix:"from:?;namespace:ns1"`
+	}
+
+	child := c.Child()
+	if err := child.Invoke(context.Background(), func(x X) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"start func(dix.X) ",
+		"  start dix.X from:?",
+		"    start dix.TB from:TBP",
+		"      provide TBP@def",
+		"      end dix.TB",
+		"    start int from:?;namespace:ns1",
+		"      end int",
+		"    end dix.X",
+		"  end func(dix.X)",
+	}
+	if !reflect.DeepEqual(tr.events, want) {
+		t.Fatalf("trace unexpected: %q", tr.events)
+	}
+}
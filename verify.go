@@ -0,0 +1,170 @@
+package dix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Verify is verifying the wiring of X with the default Container, see Container.Verify
+func Verify[X any]() error {
+	return defContainer.Verify(reflect.TypeOf((*X)(nil)).Elem())
+}
+
+// Verify is walking the dix tag graph from the root types without calling Provide or allocating instances;
+// It checks every `from:<symbol>` has a Provider in the namespace, every interface type resolved by `from:?` is bound,
+// the constructor parameters are resolvable, the tag values parse and no cycles exist;
+// The fields preset in the binding values are skipped like inject;
+// The error is joined of all problems, the struct fields problems are FieldError
+func (c *Container) Verify(types ...reflect.Type) error {
+	var errs []error
+	for _, t := range types {
+		tag := NewTag().SetSymbol(TagInvoke)
+		rs := newResolver(context.Background())
+		if e := c.verify(&verifier{done: make(map[verified]bool)}, rs, t, tag); e != nil {
+			if je, ok := e.(interface{ Unwrap() []error }); ok {
+				errs = append(errs, je.Unwrap()...)
+			} else {
+				errs = append(errs, e)
+			}
+		}
+		rs.release()
+		tag.Free()
+	}
+	return errors.Join(errs...)
+}
+
+// verifier is the state of Verify walking
+type verifier struct {
+	done map[verified]bool // done is the struct types verified without problems
+}
+
+// verified is the struct type verified in the Container
+type verified struct {
+	c *Container
+	t reflect.Type
+}
+
+// verify is checking the type resolved with tag, rs is the walking path
+func (c *Container) verify(v *verifier, rs *resolver, t reflect.Type, tag *Tag) error {
+	symbol, ns := tag.GetSymbol(), namespace(context.Background(), tag)
+	switch symbol {
+	case "":
+		return nil
+	case TagInvoke:
+	default:
+		if c.lookupProvider(symbol, ns) == nil {
+			return &ProviderError{Symbol: symbol, Namespace: ns, Err: ErrNotBound}
+		}
+		return nil
+	}
+
+	r, owner := c.lookupBinding(t, ns)
+	if r == nil {
+		if t.Kind() == reflect.Interface && t.NumMethod() > 0 {
+			return fmt.Errorf("`%s` in namespace `%s`: %w", t, ns, ErrNotBound)
+		}
+		return c.verifyFields(v, rs, t, reflect.Value{})
+	}
+
+	// the Singleton is resolved by the Container owns it
+	if r.life == Singleton {
+		c = owner
+	}
+
+	// the lazy Singleton pointer on the path is resolved, see once
+	if r.life == Singleton && !r.fn.IsValid() && r.t.Kind() == reflect.Pointer && c.lazy.Load() {
+		if rs.wiring(&r.inst) {
+			return nil
+		}
+		rs.push(frame{t: r.t, inst: &r.inst})
+		defer rs.pop()
+	}
+
+	if r.fn.IsValid() {
+		if e := rs.cycled(r.t); e != nil {
+			return e
+		}
+
+		ft := r.fn.Type()
+		var errs []error
+		for i := 0; i < ft.NumIn(); i++ {
+			ptag := NewTag().SetSymbol(TagInvoke)
+			if i < len(r.params) {
+				if _, e := ParseTag(r.params[i]); e != nil {
+					errs = append(errs, fmt.Errorf("constructor `%s` param %d `%s`: %w", ft, i, ft.In(i), e))
+					ptag.Free()
+					continue
+				}
+				ptag.Unmarshal(r.params[i])
+			}
+
+			rs.push(frame{t: r.t, param: i})
+			if e := c.verify(v, rs, ft.In(i), ptag); e != nil {
+				errs = append(errs, fmt.Errorf("constructor `%s` param %d `%s` di error: %w", ft, i, ft.In(i), e))
+			}
+			rs.pop()
+			ptag.Free()
+		}
+		if len(errs) > 0 {
+			return errors.Join(errs...)
+		}
+	}
+
+	// the fields preset in the binding value are not injected, see inject
+	var x reflect.Value
+	if !r.fn.IsValid() {
+		x = r.v
+	}
+	return c.verifyFields(v, rs, r.t, x)
+}
+
+// verifyFields is checking the dix tag fields of struct type, pointer type is dereference;
+// If x is the valid binding value its non-zero fields are skipped like inject, the result is not cached for the type
+func (c *Container) verifyFields(v *verifier, rs *resolver, t reflect.Type, x reflect.Value) error {
+	s := deref(t)
+	if s.Kind() != reflect.Struct || !x.IsValid() && v.done[verified{c, s}] {
+		return nil
+	}
+	if x.IsValid() && x.Kind() == reflect.Pointer {
+		if x.IsNil() {
+			x = reflect.Value{}
+		} else {
+			x = x.Elem()
+		}
+	}
+	if e := rs.cycled(s); e != nil {
+		return e
+	}
+
+	var errs []error
+	for i := 0; i < s.NumField(); i++ {
+		sf := s.Field(i)
+		val, ok := sf.Tag.Lookup(TagDix)
+		if !ok || !sf.IsExported() {
+			continue
+		}
+
+		tag, e := ParseTag(val)
+		if e != nil {
+			errs = appendFieldError(errs, s, &sf, val, e)
+			continue
+		}
+		if x.IsValid() && !x.Field(i).IsZero() {
+			continue
+		}
+
+		rs.push(frame{t: s, field: sf.Name})
+		if e := c.verify(v, rs, sf.Type, tag); e != nil {
+			errs = appendFieldError(errs, s, &sf, val, e)
+		}
+		rs.pop()
+	}
+
+	if len(errs) == 0 && !x.IsValid() {
+		v.done[verified{c, s}] = true
+	}
+
+	return errors.Join(errs...)
+}
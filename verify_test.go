@@ -0,0 +1,75 @@
+package dix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	c := NewContainer()
+	BindIn[Provider](c, TBProvider{})
+	BindIn[fmt.Stringer](c, TStringer{}, "stringer1", "stringer2")
+	if err := c.Verify(reflect.TypeOf(TA{})); err != nil {
+		t.Fatal(err)
+	}
+
+	type Y struct {
+		Slice []int        `dix:"from:?;slice_len:x"`
+		Str   fmt.Stringer `dix:"from:?"`
+	}
+	type X struct {
+		TB    TB           `dix:"from:TBP;namespace:ns1"`
+		Y     *Y           `dix:"from:?"`
+		Cycle TCycleA      `dix:"from:?"`
+		Repo  *TRepo       `dix:"from:?"`
+		Ok    fmt.Stringer `dix:"from:?;namespace:stringer1"`
+	}
+	if err := c.Constructor(func(cfg TConfig) *TRepo { return nil }, WithParamTags("from:cfg")); err != nil {
+		t.Fatal(err)
+	}
+
+	err := c.Verify(reflect.TypeOf(X{}))
+	je, ok := err.(interface{ Unwrap() []error })
+	if !ok || len(je.Unwrap()) != 5 {
+		t.Fatalf("verify errors unexpected: %v", err)
+	}
+
+	want := []string{"TB", "Y.Slice", "Y.Str", "Cycle.B.A", "Repo"}
+	for i, err := range je.Unwrap() {
+		fe := (*FieldError)(nil)
+		if !errors.As(err, &fe) || strings.Join(fe.Path, ".") != want[i] {
+			t.Fatalf("verify error %d unexpected: %v", i, err)
+		}
+	}
+	t.Log(err)
+}
+
+type TVerifyDB struct {
+	Conn string `dix:"from:conn"`
+}
+
+func TestVerifyPreset(t *testing.T) {
+	// the field preset in the binding value is not injected, so its Provider is not required
+	c := NewContainer()
+	BindIn[*TVerifyDB](c, &TVerifyDB{Conn: "preset"})
+	if _, err := Resolve[*TVerifyDB](context.Background(), c); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Verify(reflect.TypeOf(&TVerifyDB{})); err != nil {
+		t.Fatalf("verify preset unexpected: %v", err)
+	}
+
+	// the zero field is still checked, also for the unbound type
+	c = NewContainer()
+	BindIn[*TVerifyDB](c, &TVerifyDB{})
+	if err := c.Verify(reflect.TypeOf(&TVerifyDB{})); err == nil {
+		t.Fatal("verify zero field expected error")
+	}
+	if err := NewContainer().Verify(reflect.TypeOf(TVerifyDB{})); err == nil {
+		t.Fatal("verify unbound type expected error")
+	}
+}